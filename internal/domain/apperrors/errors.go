@@ -0,0 +1,117 @@
+package apperrors
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errores base del dominio, comparables con errors.Is
+var (
+	ErrNotFound            = errors.New("recurso no encontrado")
+	ErrUpstreamUnavailable = errors.New("servicio externo no disponible")
+	ErrUpstreamRateLimited = errors.New("limite de solicitudes del servicio externo excedido")
+	ErrInvalidInput        = errors.New("entrada no valida")
+	ErrTimeout             = errors.New("tiempo de espera agotado")
+	ErrUnauthorized        = errors.New("no autorizado")
+)
+
+// Codigos legibles por maquina expuestos a los clientes
+const (
+	CodeNotFound            = "NOT_FOUND"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamRateLimited = "UPSTREAM_RATE_LIMITED"
+	CodeInvalidInput        = "INVALID_INPUT"
+	CodeTimeout             = "TIMEOUT"
+	CodeUnauthorized        = "UNAUTHORIZED"
+	CodeInternal            = "INTERNAL_ERROR"
+)
+
+// Error asocia un error base del dominio con un mensaje seguro para el cliente
+// y la causa original
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+// New crea un error del dominio sin causa
+func New(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap crea un error del dominio conservando la causa original
+func Wrap(kind error, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, 2)
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	} else if e.Kind != nil {
+		parts = append(parts, e.Kind.Error())
+	}
+	if e.Err != nil {
+		parts = append(parts, e.Err.Error())
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Unwrap permite que errors.Is encuentre tanto el tipo como la causa
+func (e *Error) Unwrap() []error {
+	wrapped := make([]error, 0, 2)
+	if e.Kind != nil {
+		wrapped = append(wrapped, e.Kind)
+	}
+	if e.Err != nil {
+		wrapped = append(wrapped, e.Err)
+	}
+	return wrapped
+}
+
+// CodeOf devuelve el codigo de cliente que corresponde al error
+func CodeOf(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrInvalidInput):
+		return CodeInvalidInput
+	case errors.Is(err, ErrUnauthorized):
+		return CodeUnauthorized
+	case errors.Is(err, ErrUpstreamRateLimited):
+		return CodeUpstreamRateLimited
+	case errors.Is(err, ErrTimeout):
+		return CodeTimeout
+	case errors.Is(err, ErrUpstreamUnavailable):
+		return CodeUpstreamUnavailable
+	default:
+		return CodeInternal
+	}
+}
+
+// MessageOf devuelve el mensaje seguro para el cliente, o el mensaje por
+// defecto del codigo si el error no trae uno propio
+func MessageOf(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Message != "" {
+		return appErr.Message
+	}
+	switch CodeOf(err) {
+	case CodeNotFound:
+		return "Recurso no encontrado"
+	case CodeInvalidInput:
+		return "Solicitud no valida"
+	case CodeUnauthorized:
+		return "No autorizado"
+	case CodeUpstreamRateLimited:
+		return "Limite de solicitudes a PokeAPI excedido, intente mas tarde"
+	case CodeTimeout:
+		return "Tiempo de espera agotado al consultar PokeAPI"
+	case CodeUpstreamUnavailable:
+		return "PokeAPI no esta disponible en este momento"
+	default:
+		return "Error interno del servidor"
+	}
+}
@@ -0,0 +1,11 @@
+package entities
+
+// CacheStats son los contadores de un espacio de nombres de la cache
+// (por ejemplo pokemon:id)
+type CacheStats struct {
+	Hits        int64 `json:"hits"`
+	StaleHits   int64 `json:"stale_hits"`
+	Misses      int64 `json:"misses"`
+	Evictions   int64 `json:"evictions"`
+	Expirations int64 `json:"expirations"`
+}
@@ -0,0 +1,52 @@
+package entities
+
+// Comparacion lado a lado de varios Pokemon. Las diferencias y proporciones
+// se calculan respecto a Baseline, el primer Pokemon obtenido
+type PokemonComparison struct {
+	Baseline string            `json:"baseline"`
+	Pokemon  []ComparedPokemon `json:"pokemon"`
+	Stats    []StatComparison  `json:"stats"`
+	Matchups []TypeMatchup     `json:"matchups"`
+	Sizes    []SizeComparison  `json:"sizes"`
+	Errors   []ComparisonError `json:"errors"`
+}
+
+type ComparedPokemon struct {
+	ID            int            `json:"id"`
+	Name          string         `json:"name"`
+	Types         []string       `json:"types"`
+	Height        int            `json:"height"`
+	Weight        int            `json:"weight"`
+	BaseStats     map[string]int `json:"base_stats"`
+	BaseStatTotal int            `json:"base_stat_total"`
+}
+
+// Valores de una estadistica por Pokemon, quien gana y la diferencia con la base
+type StatComparison struct {
+	Stat    string         `json:"stat"`
+	Values  map[string]int `json:"values"`
+	Deltas  map[string]int `json:"deltas"`
+	Winners []string       `json:"winners"`
+}
+
+// Mejor multiplicador que el atacante consigue con sus tipos contra el defensor
+type TypeMatchup struct {
+	Attacker       string  `json:"attacker"`
+	Defender       string  `json:"defender"`
+	BestType       string  `json:"best_type"`
+	Multiplier     float64 `json:"multiplier"`
+	SuperEffective bool    `json:"super_effective"`
+}
+
+type SizeComparison struct {
+	Pokemon     string  `json:"pokemon"`
+	HeightRatio float64 `json:"height_ratio"`
+	WeightRatio float64 `json:"weight_ratio"`
+}
+
+// Referencia que no se pudo comparar
+type ComparisonError struct {
+	Ref     string `json:"ref"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,38 @@
+package entities
+
+// Cadena evolutiva completa de una familia de Pokemon
+type EvolutionChain struct {
+	ID    int           `json:"id"`
+	Chain EvolutionNode `json:"chain"`
+}
+
+// Nodo del arbol evolutivo. Conditions describe como se llega a esta especie
+// desde su nodo padre (vacio en la raiz)
+type EvolutionNode struct {
+	SpeciesID  int                  `json:"species_id"`
+	Species    string               `json:"species"`
+	IsBaby     bool                 `json:"is_baby"`
+	Conditions []EvolutionCondition `json:"conditions"`
+	EvolvesTo  []EvolutionNode      `json:"evolves_to"`
+}
+
+// Requisitos para evolucionar; solo se informan los que aplican
+type EvolutionCondition struct {
+	Trigger               string `json:"trigger"`
+	MinLevel              *int   `json:"min_level,omitempty"`
+	Item                  string `json:"item,omitempty"`
+	HeldItem              string `json:"held_item,omitempty"`
+	MinHappiness          *int   `json:"min_happiness,omitempty"`
+	MinAffection          *int   `json:"min_affection,omitempty"`
+	MinBeauty             *int   `json:"min_beauty,omitempty"`
+	TimeOfDay             string `json:"time_of_day,omitempty"`
+	KnownMove             string `json:"known_move,omitempty"`
+	KnownMoveType         string `json:"known_move_type,omitempty"`
+	Location              string `json:"location,omitempty"`
+	Gender                *int   `json:"gender,omitempty"`
+	PartySpecies          string `json:"party_species,omitempty"`
+	TradeSpecies          string `json:"trade_species,omitempty"`
+	NeedsOverworldRain    bool   `json:"needs_overworld_rain,omitempty"`
+	TurnUpsideDown        bool   `json:"turn_upside_down,omitempty"`
+	RelativePhysicalStats *int   `json:"relative_physical_stats,omitempty"`
+}
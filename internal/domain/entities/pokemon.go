@@ -1,16 +1,82 @@
 package entities
 
-import "time"
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Entidad principal del dominio
 type Pokemon struct {
-	ID      int     `json:"id"`
-	Name    string  `json:"name"`
-	Height  int     `json:"height"`
-	Weight  int     `json:"weight"`
-	Types   []Type  `json:"types"`
-	Sprites Sprites `json:"sprites"`
-	BaseExp int     `json:"base_experience"`
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Order     int             `json:"order"`
+	Height    int             `json:"height"`
+	Weight    int             `json:"weight"`
+	Types     []Type          `json:"types"`
+	Sprites   Sprites         `json:"sprites"`
+	BaseExp   int             `json:"base_experience"`
+	Stats     []Stat          `json:"stats"`
+	Abilities []Ability       `json:"abilities"`
+	Moves     []Move          `json:"moves,omitempty"`
+	HeldItems []HeldItem      `json:"held_items"`
+	Forms     []NamedResource `json:"forms"`
+	Species   NamedResource   `json:"species"`
+}
+
+// SpeciesID devuelve el ID de la especie del Pokemon. Las formas alternativas
+// (ID 10001 en adelante) no comparten ID con su especie. Los datos guardados
+// antes de incluir species usan el ID del propio Pokemon
+func (p *Pokemon) SpeciesID() int {
+	if id := IDFromURL(p.Species.URL); id != 0 {
+		return id
+	}
+	return p.ID
+}
+
+// Recurso con nombre y URL de PokeAPI
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Nombres de las estadisticas base en el orden de PokeAPI
+var StatNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// Estadistica base con sus puntos de esfuerzo
+type Stat struct {
+	BaseStat int           `json:"base_stat"`
+	Effort   int           `json:"effort"`
+	Stat     NamedResource `json:"stat"`
+}
+
+type Ability struct {
+	Ability  NamedResource `json:"ability"`
+	IsHidden bool          `json:"is_hidden"`
+	Slot     int           `json:"slot"`
+}
+
+type Move struct {
+	Move                NamedResource       `json:"move"`
+	VersionGroupDetails []MoveVersionDetail `json:"version_group_details"`
+}
+
+// Como y a que nivel se aprende un movimiento en un grupo de versiones
+type MoveVersionDetail struct {
+	LevelLearnedAt  int           `json:"level_learned_at"`
+	MoveLearnMethod NamedResource `json:"move_learn_method"`
+	VersionGroup    NamedResource `json:"version_group"`
+}
+
+type HeldItem struct {
+	Item           NamedResource     `json:"item"`
+	VersionDetails []HeldItemVersion `json:"version_details"`
+}
+
+type HeldItemVersion struct {
+	Rarity  int           `json:"rarity"`
+	Version NamedResource `json:"version"`
 }
 
 type Type struct {
@@ -28,6 +94,45 @@ type Sprites struct {
 	BackDefault  string `json:"back_default"`
 }
 
+// WithoutMoves devuelve una copia sin la lista de movimientos, sin modificar
+// la instancia original (que puede estar compartida en cache)
+func (p *Pokemon) WithoutMoves() *Pokemon {
+	copied := *p
+	copied.Moves = nil
+	return &copied
+}
+
+// TypeNames devuelve los nombres de los tipos del Pokemon ordenados por slot
+func (p *Pokemon) TypeNames() []string {
+	types := make([]Type, len(p.Types))
+	copy(types, p.Types)
+	sort.Slice(types, func(i, j int) bool { return types[i].Slot < types[j].Slot })
+
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.Type.Name)
+	}
+	return names
+}
+
+// BaseStats devuelve las estadisticas base indexadas por nombre
+func (p *Pokemon) BaseStats() map[string]int {
+	stats := make(map[string]int, len(p.Stats))
+	for _, s := range p.Stats {
+		stats[s.Stat.Name] = s.BaseStat
+	}
+	return stats
+}
+
+// BaseStatTotal suma todas las estadisticas base
+func (p *Pokemon) BaseStatTotal() int {
+	total := 0
+	for _, s := range p.Stats {
+		total += s.BaseStat
+	}
+	return total
+}
+
 // Lista paginada de Pokemon
 type PokemonList struct {
 	Count    int             `json:"count"`
@@ -41,13 +146,90 @@ type PokemonResult struct {
 	URL  string `json:"url"`
 }
 
-// Elemento en cache con TTL
+// ID extrae el ID de la URL de PokeAPI (.../pokemon/25/); 0 si no tiene
+func (r PokemonResult) ID() int {
+	return IDFromURL(r.URL)
+}
+
+// IDFromURL extrae el ID numerico final de una URL de PokeAPI; 0 si no tiene
+func IDFromURL(url string) int {
+	path := strings.TrimSuffix(url, "/")
+	id, err := strconv.Atoi(path[strings.LastIndex(path, "/")+1:])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// Pagina de resultados de busqueda con el total de coincidencias. Los
+// candidatos cuyo detalle no se pudo obtener se informan en Errors
+type SearchResult struct {
+	Total   int           `json:"total"`
+	Results []*Pokemon    `json:"results"`
+	Errors  []SearchError `json:"errors,omitempty"`
+
+	// Posicion de la pagina en el ranking y sus candidatos extremos
+	Offset int          `json:"offset"`
+	First  *SearchMatch `json:"first,omitempty"`
+	Last   *SearchMatch `json:"last,omitempty"`
+}
+
+// Candidato de la busqueda con su relevancia
+type SearchMatch struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+// RanksBefore indica si m va antes que other en los resultados: mayor
+// relevancia, luego el nombre mas corto y por ultimo el orden alfabetico
+func (m SearchMatch) RanksBefore(other SearchMatch) bool {
+	if m.Score != other.Score {
+		return m.Score > other.Score
+	}
+	if len(m.Name) != len(other.Name) {
+		return len(m.Name) < len(other.Name)
+	}
+	return m.Name < other.Name
+}
+
+// Complete indica si se obtuvieron todos los candidatos de la pagina
+func (r *SearchResult) Complete() bool {
+	return len(r.Errors) == 0
+}
+
+// Candidato de la busqueda que no se pudo obtener
+type SearchError struct {
+	Name    string `json:"name"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Elemento en cache con TTL. Pasado ExpiresAt (TTL blando) el dato sigue
+// sirviendose como vencido hasta StaleUntil (TTL duro) mientras se refresca
 type CacheItem struct {
-	Data      interface{}
-	ExpiresAt time.Time
+	Data       interface{}
+	ExpiresAt  time.Time
+	StaleUntil time.Time
+	Validators Validators
+}
+
+// Validadores HTTP con los que se puede preguntar a PokeAPI si un dato cambio
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero indica que no hay validadores con los que revalidar
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
 }
 
 // Verifica si el item del cache ha expirado
 func (c *CacheItem) IsExpired() bool {
 	return time.Now().After(c.ExpiresAt)
 }
+
+// Verifica si el item ya no puede servirse ni siquiera como vencido
+func (c *CacheItem) IsStaleExpired() bool {
+	return time.Now().After(c.StaleUntil)
+}
@@ -0,0 +1,18 @@
+package entities
+
+// Datos de la especie de un Pokemon
+type Species struct {
+	ID               int      `json:"id"`
+	Name             string   `json:"name"`
+	Genus            string   `json:"genus"`
+	FlavorText       string   `json:"flavor_text"`
+	Language         string   `json:"language"`
+	CaptureRate      int      `json:"capture_rate"`
+	BaseHappiness    *int     `json:"base_happiness"`
+	GrowthRate       string   `json:"growth_rate"`
+	EggGroups        []string `json:"egg_groups"`
+	IsLegendary      bool     `json:"is_legendary"`
+	IsMythical       bool     `json:"is_mythical"`
+	Habitat          string   `json:"habitat"`
+	EvolutionChainID int      `json:"evolution_chain_id"`
+}
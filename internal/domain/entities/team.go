@@ -0,0 +1,50 @@
+package entities
+
+// Resultado del analisis de un equipo de hasta seis Pokemon
+type TeamAnalysis struct {
+	Members              []TeamMember      `json:"members"`
+	Coverage             TeamCoverage      `json:"coverage"`
+	Defense              []TeamTypeDefense `json:"defense"`
+	SharedWeaknesses     []string          `json:"shared_weaknesses"`
+	UnresolvedWeaknesses []string          `json:"unresolved_weaknesses"`
+	Stats                TeamStats         `json:"stats"`
+	MissingTypes         []string          `json:"missing_types"`
+	Suggestions          []TypeSuggestion  `json:"suggestions"`
+}
+
+type TeamMember struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Types         []string `json:"types"`
+	BaseStatTotal int      `json:"base_stat_total"`
+}
+
+// Cobertura ofensiva usando los tipos propios de los miembros (STAB)
+type TeamCoverage struct {
+	SuperEffective []string `json:"super_effective"`
+	NotCovered     []string `json:"not_covered"`
+}
+
+// Cuantos miembros son debiles, resisten o son inmunes a un tipo atacante
+type TeamTypeDefense struct {
+	Type    string `json:"type"`
+	Weak    int    `json:"weak"`
+	Resist  int    `json:"resist"`
+	Immune  int    `json:"immune"`
+	Neutral int    `json:"neutral"`
+}
+
+type TeamStats struct {
+	Totals               map[string]int     `json:"totals"`
+	Averages             map[string]float64 `json:"averages"`
+	BaseStatTotal        int                `json:"base_stat_total"`
+	AverageBaseStatTotal float64            `json:"average_base_stat_total"`
+}
+
+// Tipo sugerido para el equipo con el motivo
+type TypeSuggestion struct {
+	Type    string   `json:"type"`
+	Resists []string `json:"resists"`
+	Covers  []string `json:"covers"`
+	Score   int      `json:"score"`
+}
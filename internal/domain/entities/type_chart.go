@@ -0,0 +1,146 @@
+package entities
+
+// Los 18 tipos elementales con relaciones de daño en los juegos
+var AllTypes = []string{
+	"normal", "fighting", "flying", "poison", "ground", "rock",
+	"bug", "ghost", "steel", "fire", "water", "grass",
+	"electric", "psychic", "ice", "dragon", "dark", "fairy",
+}
+
+// Tipo elemental con sus relaciones de daño
+type PokemonType struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	DamageRelations DamageRelations `json:"damage_relations"`
+}
+
+type DamageRelations struct {
+	DoubleDamageTo   []string `json:"double_damage_to"`
+	HalfDamageTo     []string `json:"half_damage_to"`
+	NoDamageTo       []string `json:"no_damage_to"`
+	DoubleDamageFrom []string `json:"double_damage_from"`
+	HalfDamageFrom   []string `json:"half_damage_from"`
+	NoDamageFrom     []string `json:"no_damage_from"`
+}
+
+// Multiplicadores de un tipo al atacar y al defender
+type TypeDetail struct {
+	Type               *PokemonType       `json:"type"`
+	AttackMultipliers  map[string]float64 `json:"attack_multipliers"`
+	DefenseMultipliers map[string]float64 `json:"defense_multipliers"`
+}
+
+// Multiplicadores combinados que recibe una combinacion de tipos,
+// agrupados por valor (x0, x0.25, x0.5, x1, x2, x4)
+type DefensiveProfile struct {
+	Types           []string           `json:"types"`
+	Multipliers     map[string]float64 `json:"multipliers"`
+	Immune          []string           `json:"immune"`
+	QuarterDamage   []string           `json:"quarter_damage"`
+	HalfDamage      []string           `json:"half_damage"`
+	Neutral         []string           `json:"neutral"`
+	DoubleDamage    []string           `json:"double_damage"`
+	QuadrupleDamage []string           `json:"quadruple_damage"`
+}
+
+// Debilidades de un Pokemon concreto
+type PokemonWeaknesses struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	DefensiveProfile
+}
+
+// Matriz de efectividad atacante -> defensor
+type TypeChart struct {
+	matrix map[string]map[string]float64
+}
+
+// NewTypeChart construye la matriz a partir de las relaciones ofensivas de
+// cada tipo; las combinaciones no listadas valen 1
+func NewTypeChart(types []*PokemonType) *TypeChart {
+	chart := &TypeChart{matrix: make(map[string]map[string]float64, len(types))}
+	for _, t := range types {
+		row := make(map[string]float64)
+		for _, defender := range t.DamageRelations.DoubleDamageTo {
+			row[defender] = 2
+		}
+		for _, defender := range t.DamageRelations.HalfDamageTo {
+			row[defender] = 0.5
+		}
+		for _, defender := range t.DamageRelations.NoDamageTo {
+			row[defender] = 0
+		}
+		chart.matrix[t.Name] = row
+	}
+	return chart
+}
+
+// Effectiveness devuelve el multiplicador de un tipo atacante sobre un tipo defensor
+func (c *TypeChart) Effectiveness(attacker, defender string) float64 {
+	if multiplier, ok := c.matrix[attacker][defender]; ok {
+		return multiplier
+	}
+	return 1
+}
+
+// Multiplier combina la efectividad sobre todos los tipos del defensor
+func (c *TypeChart) Multiplier(attacker string, defenders []string) float64 {
+	multiplier := 1.0
+	for _, defender := range defenders {
+		multiplier *= c.Effectiveness(attacker, defender)
+	}
+	return multiplier
+}
+
+// AttackMultipliers devuelve la fila del tipo atacante contra cada tipo
+func (c *TypeChart) AttackMultipliers(attacker string) map[string]float64 {
+	row := make(map[string]float64, len(AllTypes))
+	for _, defender := range AllTypes {
+		row[defender] = c.Effectiveness(attacker, defender)
+	}
+	return row
+}
+
+// DefenseMultipliers devuelve lo que recibe el tipo defensor de cada atacante
+func (c *TypeChart) DefenseMultipliers(defender string) map[string]float64 {
+	column := make(map[string]float64, len(AllTypes))
+	for _, attacker := range AllTypes {
+		column[attacker] = c.Effectiveness(attacker, defender)
+	}
+	return column
+}
+
+// DefensiveProfile calcula los multiplicadores combinados para uno o dos tipos
+func (c *TypeChart) DefensiveProfile(defenders []string) *DefensiveProfile {
+	profile := &DefensiveProfile{
+		Types:           defenders,
+		Multipliers:     make(map[string]float64, len(AllTypes)),
+		Immune:          []string{},
+		QuarterDamage:   []string{},
+		HalfDamage:      []string{},
+		Neutral:         []string{},
+		DoubleDamage:    []string{},
+		QuadrupleDamage: []string{},
+	}
+
+	for _, attacker := range AllTypes {
+		multiplier := c.Multiplier(attacker, defenders)
+		profile.Multipliers[attacker] = multiplier
+		switch {
+		case multiplier == 0:
+			profile.Immune = append(profile.Immune, attacker)
+		case multiplier <= 0.25:
+			profile.QuarterDamage = append(profile.QuarterDamage, attacker)
+		case multiplier < 1:
+			profile.HalfDamage = append(profile.HalfDamage, attacker)
+		case multiplier == 1:
+			profile.Neutral = append(profile.Neutral, attacker)
+		case multiplier < 4:
+			profile.DoubleDamage = append(profile.DoubleDamage, attacker)
+		default:
+			profile.QuadrupleDamage = append(profile.QuadrupleDamage, attacker)
+		}
+	}
+
+	return profile
+}
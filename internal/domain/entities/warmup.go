@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Estados del precalentamiento de la cache
+const (
+	WarmupPending  = "pending"
+	WarmupRunning  = "running"
+	WarmupDone     = "done"
+	WarmupDisabled = "disabled"
+)
+
+// WarmupStatus es el progreso del precalentamiento de la cache
+type WarmupStatus struct {
+	State     string `json:"state"`
+	Ready     bool   `json:"ready"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	// Veces que se pauso por el limite de solicitudes de PokeAPI
+	RateLimited int        `json:"rate_limited"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
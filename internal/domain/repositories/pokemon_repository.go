@@ -10,6 +10,8 @@ import (
 type PokemonRepository interface {
 	GetByID(ctx context.Context, id int) (*entities.Pokemon, error)
 	GetByName(ctx context.Context, name string) (*entities.Pokemon, error)
+	GetSpeciesByID(ctx context.Context, id int) (*entities.Species, error)
+	GetSpeciesByName(ctx context.Context, name string) (*entities.Species, error)
+	GetEvolutionChain(ctx context.Context, id int) (*entities.EvolutionChain, error)
 	GetList(ctx context.Context, limit, offset int) (*entities.PokemonList, error)
-	SearchByTitle(ctx context.Context, title string, limit, offset int) ([]*entities.Pokemon, error)
 }
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// ErrNotModified indica que el origen confirmo que el dato en cache no cambio
+var ErrNotModified = errors.New("recurso no modificado")
+
+type revalidationKey struct{}
+
+// Revalidation acompaña una carga: lleva los validadores del dato en cache
+// para hacer una solicitud condicional y recibe los de la respuesta
+type Revalidation struct {
+	mutex    sync.Mutex
+	cached   entities.Validators
+	received entities.Validators
+	claimed  bool
+}
+
+// WithRevalidation adjunta al contexto una revalidacion con los validadores
+// del dato en cache (vacios si no hay dato)
+func WithRevalidation(ctx context.Context, cached entities.Validators) (context.Context, *Revalidation) {
+	revalidation := &Revalidation{cached: cached}
+	return context.WithValue(ctx, revalidationKey{}, revalidation), revalidation
+}
+
+// RevalidationFrom devuelve la revalidacion del contexto solo a la primera
+// solicitud que la pide, para que una carga compuesta no mezcle validadores
+func RevalidationFrom(ctx context.Context) (*Revalidation, bool) {
+	revalidation, ok := ctx.Value(revalidationKey{}).(*Revalidation)
+	if !ok {
+		return nil, false
+	}
+
+	revalidation.mutex.Lock()
+	defer revalidation.mutex.Unlock()
+
+	if revalidation.claimed {
+		return nil, false
+	}
+	revalidation.claimed = true
+	return revalidation, true
+}
+
+// Cached devuelve los validadores a enviar en la solicitud condicional
+func (r *Revalidation) Cached() entities.Validators {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.cached
+}
+
+// Receive registra los validadores de la respuesta
+func (r *Revalidation) Receive(validators entities.Validators) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.received = validators
+}
+
+// Received devuelve los validadores con que guardar el dato
+func (r *Revalidation) Received() entities.Validators {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.received
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// Acceso a los tipos elementales y sus relaciones de daño
+type TypeRepository interface {
+	GetType(ctx context.Context, name string) (*entities.PokemonType, error)
+}
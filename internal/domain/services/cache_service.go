@@ -1,11 +1,76 @@
 package services
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
 
 // Operaciones de cache
 type CacheService interface {
 	Get(ctx context.Context, key string) (interface{}, bool)
-	Set(ctx context.Context, key string, value interface{}) error
+	// GetEntry devuelve el elemento aunque este vencido, mientras no supere su TTL duro
+	GetEntry(ctx context.Context, key string) (*entities.CacheItem, bool)
+	// Set guarda el valor. Los backends que serializan solo aceptan valores
+	// Encodable (como los que guarda TypedCache) o []byte
+	Set(ctx context.Context, key string, value interface{}, opts ...SetOption) error
 	Delete(ctx context.Context, key string) error
 	Clear(ctx context.Context) error
+	// Keys devuelve las claves que empiezan con prefix
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Fuente de contadores de la cache por espacio de nombres
+type CacheStatsProvider interface {
+	CacheStats() map[string]entities.CacheStats
+}
+
+// Encodable lo implementan los valores que saben serializarse, para que los
+// backends externos no tengan que adivinar su tipo. Al leerlos de vuelta esos
+// backends devuelven los bytes tal cual
+type Encodable interface {
+	Encode() ([]byte, error)
+}
+
+// Opciones de escritura en cache
+type SetOptions struct {
+	// TTL blando del elemento; cero usa el TTL por defecto del backend
+	TTL time.Duration
+	// Fraccion maxima de variacion aleatoria del TTL (0.1 = ±10%)
+	Jitter float64
+	// Validadores HTTP del dato, para revalidarlo cuando venza
+	Validators entities.Validators
+}
+
+type SetOption func(*SetOptions)
+
+// WithTTL fija el TTL blando de un elemento
+func WithTTL(ttl time.Duration) SetOption {
+	return func(o *SetOptions) {
+		o.TTL = ttl
+	}
+}
+
+// WithJitter varia el TTL al azar en hasta ±fraction para repartir los vencimientos
+func WithJitter(fraction float64) SetOption {
+	return func(o *SetOptions) {
+		o.Jitter = fraction
+	}
+}
+
+// WithValidators guarda junto al elemento los validadores con que se obtuvo
+func WithValidators(validators entities.Validators) SetOption {
+	return func(o *SetOptions) {
+		o.Validators = validators
+	}
+}
+
+// ApplySetOptions combina las opciones recibidas por Set
+func ApplySetOptions(opts []SetOption) SetOptions {
+	var options SetOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
 }
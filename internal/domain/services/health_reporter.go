@@ -0,0 +1,6 @@
+package services
+
+// Componente que informa su estado en /health
+type HealthReporter interface {
+	Health() (healthy bool, details map[string]interface{})
+}
@@ -0,0 +1,6 @@
+package services
+
+// Componente que expone metricas en /metrics
+type MetricsProvider interface {
+	Metrics() map[string]interface{}
+}
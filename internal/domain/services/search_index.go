@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// Indice de nombres de Pokemon para busquedas
+type SearchIndex interface {
+	// Search devuelve los candidatos que coinciden con la consulta, ordenados por relevancia
+	Search(ctx context.Context, query string) ([]entities.SearchMatch, error)
+	// Listing devuelve el listado completo ordenado por ID; no debe modificarse
+	Listing(ctx context.Context) ([]entities.PokemonResult, error)
+	// Refresh vuelve a construir el indice desde PokeAPI
+	Refresh(ctx context.Context) error
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TTLPolicy asigna el TTL de cada clave segun el prefijo mas largo que
+// coincida y agrega una variacion aleatoria para que las claves guardadas a la
+// vez no venzan todas juntas
+type TTLPolicy struct {
+	prefixes []string
+	ttls     map[string]time.Duration
+	jitter   float64
+}
+
+// NewTTLPolicy crea la politica. jitter es la fraccion maxima de variacion
+// del TTL (0.1 = ±10%)
+func NewTTLPolicy(ttls map[string]time.Duration, jitter float64) *TTLPolicy {
+	prefixes := make([]string, 0, len(ttls))
+	for prefix := range ttls {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	return &TTLPolicy{
+		prefixes: prefixes,
+		ttls:     ttls,
+		jitter:   jitter,
+	}
+}
+
+// TTL devuelve el TTL del prefijo mas largo que coincide con key
+func (p *TTLPolicy) TTL(key string) (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return p.ttls[prefix], true
+		}
+	}
+	return 0, false
+}
+
+// Options devuelve las opciones de Set para key; sin politica que coincida
+// se usa el TTL por defecto del backend, con la misma variacion
+func (p *TTLPolicy) Options(key string) []SetOption {
+	if p == nil {
+		return nil
+	}
+
+	var opts []SetOption
+	if ttl, found := p.TTL(key); found {
+		opts = append(opts, WithTTL(ttl))
+	}
+	if p.jitter > 0 {
+		opts = append(opts, WithJitter(p.jitter))
+	}
+	return opts
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// Codec serializa los valores de un tipo concreto
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec serializa con encoding/json
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// Entry es un valor tipado leido de la cache junto con sus vencimientos y
+// los validadores con que se obtuvo
+type Entry[T any] struct {
+	Value      T
+	ExpiresAt  time.Time
+	StaleUntil time.Time
+	Validators entities.Validators
+}
+
+// IsExpired indica si el valor supero su TTL blando
+func (e Entry[T]) IsExpired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
+
+// TypedCache guarda valores de un solo tipo bajo un espacio de nombres de
+// claves, con su propio codec y opciones de escritura (TTL, variacion)
+type TypedCache[T any] struct {
+	cache     CacheService
+	namespace string
+	codec     Codec[T]
+	opts      []SetOption
+}
+
+// NewTypedCache crea la cache tipada. Las claves quedan como namespace:id y
+// opts se aplican en cada Set; sin WithTTL se usa el TTL por defecto del backend
+func NewTypedCache[T any](cache CacheService, namespace string, codec Codec[T], opts ...SetOption) *TypedCache[T] {
+	return &TypedCache[T]{
+		cache:     cache,
+		namespace: namespace,
+		codec:     codec,
+		opts:      opts,
+	}
+}
+
+// Key devuelve la clave completa de id en el backend
+func (c *TypedCache[T]) Key(id string) string {
+	return c.namespace + ":" + id
+}
+
+// Get devuelve el valor si existe y no supero su TTL blando
+func (c *TypedCache[T]) Get(ctx context.Context, id string) (T, bool) {
+	entry, found := c.GetEntry(ctx, id)
+	if !found || entry.IsExpired() {
+		var zero T
+		return zero, false
+	}
+	return entry.Value, true
+}
+
+// GetEntry devuelve el valor aunque este vencido, mientras no supere su TTL duro
+func (c *TypedCache[T]) GetEntry(ctx context.Context, id string) (Entry[T], bool) {
+	key := c.Key(id)
+	item, found := c.cache.GetEntry(ctx, key)
+	if !found {
+		return Entry[T]{}, false
+	}
+
+	var value T
+	switch data := item.Data.(type) {
+	case encoded[T]:
+		// Backends en memoria: el valor se guarda sin serializar
+		value = data.value
+	case []byte:
+		decoded, err := c.codec.Decode(data)
+		if err != nil {
+			log.Printf("no se pudo decodificar el elemento de cache: %s: %v", key, err)
+			return Entry[T]{}, false
+		}
+		value = decoded
+	default:
+		log.Printf("tipo inesperado en cache: %s: %T", key, item.Data)
+		return Entry[T]{}, false
+	}
+
+	return Entry[T]{Value: value, ExpiresAt: item.ExpiresAt, StaleUntil: item.StaleUntil, Validators: item.Validators}, true
+}
+
+// Set guarda el valor con las opciones del espacio de nombres; opts se
+// aplican despues y pueden sobrescribirlas
+func (c *TypedCache[T]) Set(ctx context.Context, id string, value T, opts ...SetOption) error {
+	return c.cache.Set(ctx, c.Key(id), encoded[T]{value: value, codec: c.codec}, append(slices.Clip(c.opts), opts...)...)
+}
+
+// Delete elimina el valor
+func (c *TypedCache[T]) Delete(ctx context.Context, id string) error {
+	return c.cache.Delete(ctx, c.Key(id))
+}
+
+// Valor junto con su codec; los backends que serializan lo codifican
+type encoded[T any] struct {
+	value T
+	codec Codec[T]
+}
+
+func (e encoded[T]) Encode() ([]byte, error) {
+	data, err := e.codec.Encode(e.value)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo serializar el valor: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+// Elemento serializado junto con sus vencimientos. El valor lo codifica quien
+// lo guarda (services.Encodable), por lo que al leerlo se devuelven los bytes
+type storedItem struct {
+	ExpiresAt    time.Time `json:"expires_at"`
+	StaleUntil   time.Time `json:"stale_until"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Data         []byte    `json:"data"`
+}
+
+// Obtiene los bytes de un valor serializable
+func encodeValue(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case services.Encodable:
+		return v.Encode()
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("tipo no serializable en cache: %T", value)
+	}
+}
+
+func encodeItem(item *entities.CacheItem) ([]byte, error) {
+	data, err := encodeValue(item.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(storedItem{
+		ExpiresAt:    item.ExpiresAt,
+		StaleUntil:   item.StaleUntil,
+		ETag:         item.Validators.ETag,
+		LastModified: item.Validators.LastModified,
+		Data:         data,
+	})
+}
+
+func decodeItem(payload []byte) (*entities.CacheItem, error) {
+	var stored storedItem
+	if err := json.Unmarshal(payload, &stored); err != nil {
+		return nil, fmt.Errorf("no se pudo deserializar el elemento: %w", err)
+	}
+
+	return &entities.CacheItem{
+		Data:       stored.Data,
+		ExpiresAt:  stored.ExpiresAt,
+		StaleUntil: stored.StaleUntil,
+		Validators: entities.Validators{
+			ETag:         stored.ETag,
+			LastModified: stored.LastModified,
+		},
+	}, nil
+}
+
+// Calcula los vencimientos de un elemento nuevo segun las opciones de Set
+func newItem(value interface{}, ttl, staleTTL time.Duration, opts []services.SetOption) *entities.CacheItem {
+	options := services.ApplySetOptions(opts)
+	if options.TTL > 0 {
+		ttl = options.TTL
+	}
+	if options.Jitter > 0 {
+		ttl += time.Duration((rand.Float64()*2 - 1) * options.Jitter * float64(ttl))
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	return &entities.CacheItem{
+		Data:       value,
+		ExpiresAt:  expiresAt,
+		StaleUntil: expiresAt.Add(staleTTL),
+		Validators: options.Validators,
+	}
+}
@@ -0,0 +1,444 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	diskCacheFile = "cache.log"
+
+	// Se compacta cuando el archivo supera este tamaño y al menos la mitad
+	// son registros obsoletos
+	minCompactSize = 1 << 20
+)
+
+// Operaciones del registro en disco
+const (
+	diskOpSet    = "set"
+	diskOpDelete = "del"
+)
+
+// Registro del archivo: una linea JSON por operacion
+type diskRecord struct {
+	Op         string          `json:"op"`
+	Key        string          `json:"key,omitempty"`
+	StaleUntil time.Time       `json:"stale_until,omitempty"`
+	Item       json.RawMessage `json:"item,omitempty"`
+}
+
+// Posicion del ultimo registro vigente de una clave; el valor se lee del
+// disco solo cuando se pide
+type diskEntry struct {
+	offset     int64
+	length     int64
+	staleUntil time.Time
+}
+
+// DiskCache guarda la cache en un archivo de solo anexado para que sobreviva
+// a los reinicios. En memoria solo se mantiene el indice de posiciones,
+// limitado en cantidad de claves y con desalojo LRU. Los desalojos tambien se
+// anotan en el archivo para que no reaparezcan al reconstruir el indice
+type DiskCache struct {
+	mutex sync.Mutex
+	dir   string
+
+	// Las lecturas de valores se hacen fuera de mutex; fileMutex evita que el
+	// archivo se reemplace o se vacie mientras tanto
+	fileMutex sync.RWMutex
+	file      *os.File
+	size      int64
+	liveBytes int64
+	index     *lru.Cache[string, diskEntry]
+	ttl       time.Duration
+	staleTTL  time.Duration
+	onRemove  removalHook
+}
+
+// NewDiskCache abre (o crea) la cache en dir y reconstruye el indice a partir
+// del archivo existente
+func NewDiskCache(dir string, size int, ttl, staleTTL time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("no se pudo crear el directorio de cache: %w", err)
+	}
+
+	cache := &DiskCache{
+		dir:      dir,
+		ttl:      ttl,
+		staleTTL: staleTTL,
+	}
+
+	index, err := lru.NewWithEvict[string, diskEntry](size, func(_ string, entry diskEntry) {
+		cache.liveBytes -= entry.length
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo crear el indice de la cache en disco: %w", err)
+	}
+	cache.index = index
+
+	file, err := os.OpenFile(filepath.Join(dir, diskCacheFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir la cache en disco: %w", err)
+	}
+	cache.file = file
+
+	if err := cache.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	go cache.cleanupExpired()
+
+	return cache, nil
+}
+
+func (c *DiskCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	item, found := c.GetEntry(ctx, key)
+	if !found || item.IsExpired() {
+		return nil, false
+	}
+	return item.Data, true
+}
+
+// GetEntry busca la posicion en el indice bajo el mutex y lee y decodifica el
+// valor despues de liberarlo, para no bloquear al resto durante la lectura
+func (c *DiskCache) GetEntry(ctx context.Context, key string) (*entities.CacheItem, bool) {
+	c.mutex.Lock()
+	entry, found := c.index.Get(key)
+	if !found {
+		c.mutex.Unlock()
+		return nil, false
+	}
+	if time.Now().After(entry.staleUntil) {
+		c.index.Remove(key)
+		c.onRemove.notify(key, removedExpired)
+		c.mutex.Unlock()
+		return nil, false
+	}
+	// La posicion solo es valida en el archivo actual
+	c.fileMutex.RLock()
+	c.mutex.Unlock()
+
+	line, err := c.readLine(entry)
+	c.fileMutex.RUnlock()
+
+	var item *entities.CacheItem
+	if err == nil {
+		item, err = decodeLine(line)
+	}
+	if err != nil {
+		log.Printf("elemento de cache no valido en disco: %s: %v", key, err)
+		c.mutex.Lock()
+		// Solo si nadie lo reemplazo mientras se leia
+		if current, found := c.index.Peek(key); found && current == entry {
+			c.index.Remove(key)
+		}
+		c.mutex.Unlock()
+		return nil, false
+	}
+
+	return item, true
+}
+
+func (c *DiskCache) Set(ctx context.Context, key string, value interface{}, opts ...services.SetOption) error {
+	item := newItem(value, c.ttl, c.staleTTL, opts)
+	payload, err := encodeItem(item)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	record := diskRecord{Op: diskOpSet, Key: key, StaleUntil: item.StaleUntil, Item: payload}
+	offset, length, err := c.append(record)
+	if err != nil {
+		return err
+	}
+
+	c.index.Remove(key)
+	oldest, _, hasOldest := c.index.GetOldest()
+	if evicted := c.index.Add(key, diskEntry{offset: offset, length: length, staleUntil: record.StaleUntil}); evicted && hasOldest {
+		c.onRemove.notify(oldest, removedEvicted)
+		// Sin este registro replay volveria a cargar la clave desalojada
+		if _, _, err := c.append(diskRecord{Op: diskOpDelete, Key: oldest}); err != nil {
+			return err
+		}
+	}
+	c.liveBytes += length
+
+	return c.compactIfNeeded()
+}
+
+func (c *DiskCache) Delete(ctx context.Context, key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.index.Contains(key) {
+		return nil
+	}
+	if _, _, err := c.append(diskRecord{Op: diskOpDelete, Key: key}); err != nil {
+		return err
+	}
+	c.index.Remove(key)
+
+	return c.compactIfNeeded()
+}
+
+func (c *DiskCache) Clear(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.index.Purge()
+	c.liveBytes = 0
+
+	c.fileMutex.Lock()
+	defer c.fileMutex.Unlock()
+
+	if err := c.file.Truncate(0); err != nil {
+		return fmt.Errorf("no se pudo vaciar la cache en disco: %w", err)
+	}
+	c.size = 0
+	return nil
+}
+
+func (c *DiskCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return filterKeys(c.index.Keys(), prefix), nil
+}
+
+func (c *DiskCache) setRemovalHook(hook removalHook) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.onRemove = hook
+}
+
+// Close cierra el archivo de la cache
+func (c *DiskCache) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.fileMutex.Lock()
+	defer c.fileMutex.Unlock()
+
+	return c.file.Close()
+}
+
+// Reconstruye el indice leyendo el archivo de principio a fin. Un registro
+// incompleto al final (por ejemplo tras una caida) se descarta
+func (c *DiskCache) replay() error {
+	if _, err := c.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("no se pudo leer la cache en disco: %w", err)
+	}
+
+	// Las claves vigentes se juntan antes de cargarlas en el indice para que
+	// su limite no descarte claves que siguen en el archivo
+	live := make(map[string]diskEntry)
+
+	reader := bufio.NewReader(c.file)
+	now := time.Now()
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("no se pudo leer la cache en disco: %w", err)
+		}
+
+		var record diskRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			log.Printf("registro no valido en la cache en disco en la posicion %d, se descarta el resto: %v", offset, err)
+			break
+		}
+
+		length := int64(len(line))
+		switch record.Op {
+		case diskOpSet:
+			delete(live, record.Key)
+			if now.Before(record.StaleUntil) {
+				live[record.Key] = diskEntry{offset: offset, length: length, staleUntil: record.StaleUntil}
+			}
+		case diskOpDelete:
+			delete(live, record.Key)
+		}
+		offset += length
+	}
+
+	// Descarta cualquier resto incompleto para que los nuevos registros
+	// queden alineados
+	if err := c.file.Truncate(offset); err != nil {
+		return fmt.Errorf("no se pudo reparar la cache en disco: %w", err)
+	}
+	c.size = offset
+
+	// Se cargan de la escritura mas antigua a la mas reciente. Si el limite
+	// bajo desde el ultimo arranque, las que no entran se anotan como borradas
+	keys := make([]string, 0, len(live))
+	for key := range live {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return live[keys[a]].offset < live[keys[b]].offset
+	})
+	for _, key := range keys {
+		entry := live[key]
+		oldest, _, hasOldest := c.index.GetOldest()
+		if evicted := c.index.Add(key, entry); evicted && hasOldest {
+			if _, _, err := c.append(diskRecord{Op: diskOpDelete, Key: oldest}); err != nil {
+				return err
+			}
+		}
+		c.liveBytes += entry.length
+	}
+
+	return c.compactIfNeeded()
+}
+
+// Anexa un registro y devuelve su posicion y longitud
+func (c *DiskCache) append(record diskRecord) (int64, int64, error) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no se pudo serializar el registro: %w", err)
+	}
+	line = append(line, '\n')
+
+	offset := c.size
+	if _, err := c.file.WriteAt(line, offset); err != nil {
+		return 0, 0, fmt.Errorf("no se pudo escribir en la cache en disco: %w", err)
+	}
+	c.size += int64(len(line))
+
+	return offset, int64(len(line)), nil
+}
+
+// Lee el registro de una entrada; requiere fileMutex para que el archivo no
+// cambie durante la lectura
+func (c *DiskCache) readLine(entry diskEntry) ([]byte, error) {
+	line := make([]byte, entry.length)
+	if _, err := c.file.ReadAt(line, entry.offset); err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+func decodeLine(line []byte) (*entities.CacheItem, error) {
+	var record diskRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+	return decodeItem(record.Item)
+}
+
+func (c *DiskCache) compactIfNeeded() error {
+	if c.size < minCompactSize || c.liveBytes*2 > c.size {
+		return nil
+	}
+	return c.compact()
+}
+
+// Reescribe el archivo solo con los registros vigentes, en orden de uso para
+// que el LRU se reconstruya igual al reiniciar
+func (c *DiskCache) compact() error {
+	path := filepath.Join(c.dir, diskCacheFile)
+	tmpPath := path + ".tmp"
+
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("no se pudo compactar la cache en disco: %w", err)
+	}
+
+	now := time.Now()
+	entries := make(map[string]diskEntry, c.index.Len())
+	var offset int64
+
+	// Keys devuelve las claves de la mas antigua a la mas reciente
+	for _, key := range c.index.Keys() {
+		entry, found := c.index.Peek(key)
+		if !found || now.After(entry.staleUntil) {
+			continue
+		}
+
+		line := make([]byte, entry.length)
+		if _, err := c.file.ReadAt(line, entry.offset); err == nil {
+			_, err = tmp.WriteAt(line, offset)
+		}
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("no se pudo compactar la cache en disco: %w", err)
+		}
+
+		entries[key] = diskEntry{offset: offset, length: entry.length, staleUntil: entry.staleUntil}
+		offset += entry.length
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("no se pudo compactar la cache en disco: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("no se pudo compactar la cache en disco: %w", err)
+	}
+
+	c.fileMutex.Lock()
+	c.file.Close()
+	c.file = tmp
+	c.fileMutex.Unlock()
+	c.size = offset
+
+	// Se actualizan las posiciones sin alterar el orden del LRU
+	for _, key := range c.index.Keys() {
+		if entry, found := entries[key]; found {
+			c.index.Add(key, entry)
+		} else {
+			c.index.Remove(key)
+		}
+	}
+	// Remove descuenta bytes del archivo anterior; se recalcula
+	c.liveBytes = offset
+
+	return nil
+}
+
+// Limpia periódicamente los elementos expirados del indice
+func (c *DiskCache) cleanupExpired() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.mutex.Lock()
+		now := time.Now()
+		for _, key := range c.index.Keys() {
+			if entry, found := c.index.Peek(key); found && now.After(entry.staleUntil) {
+				c.index.Remove(key)
+				c.onRemove.notify(key, removedExpired)
+			}
+		}
+		if err := c.compactIfNeeded(); err != nil {
+			log.Printf("%v", err)
+		}
+		c.mutex.Unlock()
+	}
+}
@@ -2,30 +2,52 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
 	lru "github.com/hashicorp/golang-lru/v2"
 )
 
+// Costo fijo estimado de cada elemento ademas de su valor serializado
+const entryOverhead = 128
+
 type LRUCache struct {
-	cache *lru.Cache[string, *entities.CacheItem]
-	mutex sync.RWMutex
-	ttl   time.Duration
+	cache    *lru.Cache[string, *entities.CacheItem]
+	mutex    sync.RWMutex
+	ttl      time.Duration
+	staleTTL time.Duration
+	onRemove removalHook
+
+	// Presupuesto aproximado en bytes; cero lo desactiva
+	maxBytes  int64
+	usedBytes int64
+	costs     map[string]int64
 }
 
-func NewLRUCache(size int, ttl time.Duration) (*LRUCache, error) {
-	cache, err := lru.New[string, *entities.CacheItem](size)
-	if err != nil {
-		return nil, fmt.Errorf("no se pudo crear el cache LRU: %w", err)
+// NewLRUCache crea la cache limitada a size elementos y a maxBytes bytes
+// aproximados, lo que se alcance primero. ttl es el TTL blando; staleTTL es
+// el tiempo adicional durante el cual un elemento vencido todavia puede servirse
+func NewLRUCache(size int, maxBytes int64, ttl, staleTTL time.Duration) (*LRUCache, error) {
+	lruCache := &LRUCache{
+		ttl:      ttl,
+		staleTTL: staleTTL,
+		maxBytes: maxBytes,
+		costs:    make(map[string]int64),
 	}
 
-	lruCache := &LRUCache{
-		cache: cache,
-		ttl:   ttl,
+	// Se llama en cada eliminacion, incluidas Remove y Purge
+	cache, err := lru.NewWithEvict[string, *entities.CacheItem](size, func(key string, _ *entities.CacheItem) {
+		lruCache.usedBytes -= lruCache.costs[key]
+		delete(lruCache.costs, key)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo crear el cache LRU: %w", err)
 	}
+	lruCache.cache = cache
 
 	// Iniciar limpieza periódica de elementos expirados
 	go lruCache.cleanupExpired()
@@ -42,32 +64,100 @@ func (c *LRUCache) Get(ctx context.Context, key string) (interface{}, bool) {
 		return nil, false
 	}
 
-	// Verificar si el item ha expirado
+	// Verificar si el item ha expirado; se conserva para servirlo vencido
+	// hasta que la limpieza lo elimine
 	if item.IsExpired() {
-		c.mutex.RUnlock()
-		c.mutex.Lock()
-		c.cache.Remove(key)
-		c.mutex.Unlock()
-		c.mutex.RLock()
 		return nil, false
 	}
 
 	return item.Data, true
 }
 
-func (c *LRUCache) Set(ctx context.Context, key string, value interface{}) error {
+func (c *LRUCache) GetEntry(ctx context.Context, key string) (*entities.CacheItem, bool) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	item := &entities.CacheItem{
-		Data:      value,
-		ExpiresAt: time.Now().Add(c.ttl),
+	item, found := c.cache.Get(key)
+	if !found {
+		return nil, false
 	}
 
-	c.cache.Add(key, item)
+	if item.IsStaleExpired() {
+		c.cache.Remove(key)
+		c.onRemove.notify(key, removedExpired)
+		return nil, false
+	}
+
+	return item, true
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value interface{}, opts ...services.SetOption) error {
+	c.setItem(key, newItem(value, c.ttl, c.staleTTL, opts))
 	return nil
 }
 
+// Guarda un elemento conservando sus vencimientos (copiado de otra cache).
+// El costo se calcula antes de tomar el mutex porque serializa el valor
+func (c *LRUCache) setItem(key string, item *entities.CacheItem) {
+	cost := estimateCost(key, item)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.add(key, item, cost)
+}
+
+// Agrega el elemento y desaloja los mas antiguos mientras se supere la
+// cantidad de elementos o el presupuesto de bytes
+func (c *LRUCache) add(key string, item *entities.CacheItem, cost int64) {
+	if c.maxBytes > 0 && cost > c.maxBytes {
+		// No cabe aunque se vacie la cache; se descarta la version anterior
+		c.cache.Remove(key)
+		return
+	}
+
+	// Add sobre una clave existente no llama a la funcion de desalojo
+	c.usedBytes += cost - c.costs[key]
+	c.costs[key] = cost
+
+	oldest, _, hasOldest := c.cache.GetOldest()
+	if evicted := c.cache.Add(key, item); evicted && hasOldest {
+		c.onRemove.notify(oldest, removedEvicted)
+	}
+
+	for c.maxBytes > 0 && c.usedBytes > c.maxBytes {
+		evictedKey, _, ok := c.cache.RemoveOldest()
+		if !ok {
+			break
+		}
+		c.onRemove.notify(evictedKey, removedEvicted)
+	}
+}
+
+// Metrics expone la ocupacion de la cache
+func (c *LRUCache) Metrics() map[string]interface{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return map[string]interface{}{
+		"entries":   c.cache.Len(),
+		"bytes":     c.usedBytes,
+		"max_bytes": c.maxBytes,
+	}
+}
+
+// Estima el costo de un elemento a partir de su tamaño serializado
+func estimateCost(key string, item *entities.CacheItem) int64 {
+	data, err := encodeValue(item.Data)
+	if err != nil {
+		data, err = json.Marshal(item.Data)
+	}
+	if err != nil {
+		return entryOverhead + int64(len(key))
+	}
+	return entryOverhead + int64(len(key)+len(data))
+}
+
 func (c *LRUCache) Delete(ctx context.Context, key string) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -84,6 +174,20 @@ func (c *LRUCache) Clear(ctx context.Context) error {
 	return nil
 }
 
+func (c *LRUCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return filterKeys(c.cache.Keys(), prefix), nil
+}
+
+func (c *LRUCache) setRemovalHook(hook removalHook) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.onRemove = hook
+}
+
 // Limpia periódicamente los elementos expirados
 func (c *LRUCache) cleanupExpired() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -93,8 +197,9 @@ func (c *LRUCache) cleanupExpired() {
 		c.mutex.Lock()
 		keys := c.cache.Keys()
 		for _, key := range keys {
-			if item, found := c.cache.Peek(key); found && item.IsExpired() {
+			if item, found := c.cache.Peek(key); found && item.IsStaleExpired() {
 				c.cache.Remove(key)
+				c.onRemove.notify(key, removedExpired)
 			}
 		}
 		c.mutex.Unlock()
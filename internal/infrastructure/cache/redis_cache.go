@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+// RedisCache guarda la cache en un servidor compatible con Redis para que
+// todas las replicas compartan los mismos datos
+type RedisCache struct {
+	client    *redisClient
+	keyPrefix string
+	ttl       time.Duration
+	staleTTL  time.Duration
+}
+
+// NewRedisCache crea la cache y verifica la conexion. keyPrefix aisla las
+// claves de esta aplicacion dentro del servidor
+func NewRedisCache(options RedisOptions, keyPrefix string, ttl, staleTTL time.Duration) (*RedisCache, error) {
+	cache := &RedisCache{
+		client:    newRedisClient(options),
+		keyPrefix: keyPrefix,
+		ttl:       ttl,
+		staleTTL:  staleTTL,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := cache.client.Do(ctx, "PING"); err != nil {
+		return nil, fmt.Errorf("no se pudo conectar con la cache redis en %s: %w", options.Addr, err)
+	}
+
+	return cache, nil
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	item, found := c.GetEntry(ctx, key)
+	if !found || item.IsExpired() {
+		return nil, false
+	}
+	return item.Data, true
+}
+
+func (c *RedisCache) GetEntry(ctx context.Context, key string) (*entities.CacheItem, bool) {
+	reply, err := c.client.Do(ctx, "GET", c.keyPrefix+key)
+	if err != nil {
+		if !errors.Is(err, errNilReply) {
+			log.Printf("no se pudo leer de redis: %s: %v", key, err)
+		}
+		return nil, false
+	}
+
+	payload, ok := reply.(string)
+	if !ok {
+		return nil, false
+	}
+
+	item, err := decodeItem([]byte(payload))
+	if err != nil {
+		log.Printf("elemento de cache no valido en redis: %s: %v", key, err)
+		return nil, false
+	}
+	if item.IsStaleExpired() {
+		return nil, false
+	}
+
+	return item, true
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, opts ...services.SetOption) error {
+	return c.setItem(ctx, key, newItem(value, c.ttl, c.staleTTL, opts))
+}
+
+// Guarda un elemento conservando sus vencimientos
+func (c *RedisCache) setItem(ctx context.Context, key string, item *entities.CacheItem) error {
+	payload, err := encodeItem(item)
+	if err != nil {
+		return err
+	}
+
+	// Redis elimina la clave al cumplirse el TTL duro
+	ttl := time.Until(item.StaleUntil)
+	if ttl <= 0 {
+		return nil
+	}
+	ttlMillis := strconv.FormatInt(max(ttl.Milliseconds(), 1), 10)
+	if _, err := c.client.Do(ctx, "SET", c.keyPrefix+key, string(payload), "PX", ttlMillis); err != nil {
+		return fmt.Errorf("no se pudo escribir en redis: %w", err)
+	}
+	return nil
+}
+
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if _, err := c.client.Do(ctx, "DEL", c.keyPrefix+key); err != nil {
+		return fmt.Errorf("no se pudo eliminar de redis: %w", err)
+	}
+	return nil
+}
+
+// Clear elimina solo las claves con el prefijo de la aplicacion
+func (c *RedisCache) Clear(ctx context.Context) error {
+	keys, err := c.scan(ctx, c.keyPrefix+"*")
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(keys); start += 100 {
+		end := min(start+100, len(keys))
+		args := append([]string{"DEL"}, keys[start:end]...)
+		if _, err := c.client.Do(ctx, args...); err != nil {
+			return fmt.Errorf("no se pudo limpiar redis: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *RedisCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := c.scan(ctx, c.keyPrefix+escapeGlob(prefix)+"*")
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, c.keyPrefix)
+	}
+	return keys, nil
+}
+
+func (c *RedisCache) publish(ctx context.Context, channel, message string) error {
+	if _, err := c.client.Do(ctx, "PUBLISH", channel, message); err != nil {
+		return fmt.Errorf("no se pudo publicar en redis: %w", err)
+	}
+	return nil
+}
+
+func (c *RedisCache) subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	return c.client.Subscribe(ctx, channel, handler)
+}
+
+// Escapa los comodines de MATCH para buscar un prefijo literal
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Recorre con SCAN todas las claves que cumplen el patron
+func (c *RedisCache) scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	cursor := "0"
+	for {
+		reply, err := c.client.Do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", "100")
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo recorrer las claves de redis: %w", err)
+		}
+
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return nil, errors.New("respuesta de SCAN no valida")
+		}
+		cursor, _ = parts[0].(string)
+		batch, _ := parts[1].([]interface{})
+		for _, key := range batch {
+			if k, ok := key.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+
+		if cursor == "0" || cursor == "" {
+			return keys, nil
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+func newTestRedisCache(t *testing.T, ttl, staleTTL time.Duration) (*RedisCache, *fakeRedis) {
+	t.Helper()
+
+	server := newFakeRedis(t)
+	cache, err := NewRedisCache(RedisOptions{Addr: server.Addr()}, "test:", ttl, staleTTL)
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	return cache, server
+}
+
+func TestRedisCacheSetGetDelete(t *testing.T) {
+	cache, server := newTestRedisCache(t, time.Minute, time.Minute)
+	ctx := context.Background()
+
+	if _, found := cache.Get(ctx, "pokemon:id:25"); found {
+		t.Fatal("Get encontro una clave que no existe")
+	}
+
+	if err := cache.Set(ctx, "pokemon:id:25", []byte(`{"name":"pikachu"}`)); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if !server.Has("test:pokemon:id:25") {
+		t.Fatal("Set no uso el prefijo de claves")
+	}
+
+	value, found := cache.Get(ctx, "pokemon:id:25")
+	if !found {
+		t.Fatal("Get no encontro la clave guardada")
+	}
+	if got := string(value.([]byte)); got != `{"name":"pikachu"}` {
+		t.Fatalf("Get = %s", got)
+	}
+
+	if err := cache.Delete(ctx, "pokemon:id:25"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, found := cache.Get(ctx, "pokemon:id:25"); found {
+		t.Fatal("Get encontro una clave eliminada")
+	}
+}
+
+func TestRedisCacheKeysAndClear(t *testing.T) {
+	cache, server := newTestRedisCache(t, time.Minute, time.Minute)
+	ctx := context.Background()
+
+	// Mas claves que el COUNT de SCAN para recorrer varias paginas
+	var want []string
+	for i := 0; i < 250; i++ {
+		key := "pokemon:id:" + strconv.Itoa(i)
+		want = append(want, key)
+		if err := cache.Set(ctx, key, []byte("{}")); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	for _, key := range []string{"pokemon:list:20:0", "pokemon:id*"} {
+		if err := cache.Set(ctx, key, []byte("{}")); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+	// Clave de otra aplicacion en el mismo servidor
+	server.Put("other:pokemon:id:1", "{}")
+
+	keys, err := cache.Keys(ctx, "pokemon:id:")
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	slices.Sort(keys)
+	slices.Sort(want)
+	if !slices.Equal(keys, want) {
+		t.Fatalf("Keys devolvio %d claves, se esperaban %d", len(keys), len(want))
+	}
+
+	// Los comodines del prefijo se buscan literalmente
+	keys, err = cache.Keys(ctx, "pokemon:id*")
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	if !slices.Equal(keys, []string{"pokemon:id*"}) {
+		t.Fatalf("Keys con comodin = %v", keys)
+	}
+
+	if err := cache.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	keys, err = cache.Keys(ctx, "")
+	if err != nil {
+		t.Fatalf("Keys: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("Clear dejo %d claves", len(keys))
+	}
+	if !server.Has("other:pokemon:id:1") {
+		t.Fatal("Clear elimino claves de otra aplicacion")
+	}
+}
+
+func TestRedisCacheExpiry(t *testing.T) {
+	cache, _ := newTestRedisCache(t, 50*time.Millisecond, 100*time.Millisecond)
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "pokemon:id:1", []byte("{}")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// Vencido el TTL blando Get falla pero GetEntry aun sirve el dato vencido
+	time.Sleep(75 * time.Millisecond)
+	if _, found := cache.Get(ctx, "pokemon:id:1"); found {
+		t.Fatal("Get devolvio un elemento vencido")
+	}
+	item, found := cache.GetEntry(ctx, "pokemon:id:1")
+	if !found || !item.IsExpired() {
+		t.Fatal("GetEntry no devolvio el elemento vencido dentro del TTL duro")
+	}
+
+	// Vencido el TTL duro el servidor elimina la clave
+	time.Sleep(100 * time.Millisecond)
+	if _, found := cache.GetEntry(ctx, "pokemon:id:1"); found {
+		t.Fatal("GetEntry devolvio un elemento despues del TTL duro")
+	}
+
+	// Un TTL propio reemplaza al de la cache
+	if err := cache.Set(ctx, "pokemon:id:2", []byte("{}"), services.WithTTL(time.Hour)); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	time.Sleep(75 * time.Millisecond)
+	if _, found := cache.Get(ctx, "pokemon:id:2"); !found {
+		t.Fatal("Get no respeto el TTL de WithTTL")
+	}
+}
+
+func TestRedisCacheTimeout(t *testing.T) {
+	server := newFakeRedis(t)
+	cache, err := NewRedisCache(RedisOptions{Addr: server.Addr(), Timeout: 50 * time.Millisecond}, "test:", time.Minute, time.Minute)
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	server.Stall(true)
+
+	// Sin plazo en el contexto rige el Timeout de las opciones
+	start := time.Now()
+	if err := cache.Set(context.Background(), "pokemon:id:1", []byte("{}")); err == nil {
+		t.Fatal("Set no fallo con el servidor colgado")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Set tardo %v con Timeout de 50ms", elapsed)
+	}
+
+	// Cancelar el contexto corta la espera antes del Timeout
+	cache.client.options.Timeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start = time.Now()
+	if _, found := cache.GetEntry(ctx, "pokemon:id:1"); found {
+		t.Fatal("GetEntry encontro una clave con el servidor colgado")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("GetEntry tardo %v tras cancelar el contexto", elapsed)
+	}
+
+	// Las conexiones cortadas no vuelven al pool
+	server.Stall(false)
+	if err := cache.Set(context.Background(), "pokemon:id:1", []byte("{}")); err != nil {
+		t.Fatalf("Set despues del corte: %v", err)
+	}
+	if _, found := cache.Get(context.Background(), "pokemon:id:1"); !found {
+		t.Fatal("Get no encontro la clave despues del corte")
+	}
+}
+
+func TestRedisCacheRoundTrip(t *testing.T) {
+	cache, _ := newTestRedisCache(t, time.Minute, time.Hour)
+	ctx := context.Background()
+
+	typed := services.NewTypedCache[*entities.Pokemon](cache, "pokemon:id", services.JSONCodec[*entities.Pokemon]{})
+	pokemon := &entities.Pokemon{
+		ID:      25,
+		Name:    "pikachu",
+		Types:   []entities.Type{{Slot: 1, Type: entities.TypeInfo{Name: "electric"}}},
+		Species: entities.NamedResource{Name: "pikachu", URL: "https://pokeapi.co/api/v2/pokemon-species/25/"},
+	}
+	validators := entities.Validators{ETag: `"abc"`, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+	if err := typed.Set(ctx, "25", pokemon, services.WithValidators(validators)); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	entry, found := typed.GetEntry(ctx, "25")
+	if !found {
+		t.Fatal("GetEntry no encontro el Pokemon")
+	}
+	got := entry.Value
+	if got.ID != 25 || got.Name != "pikachu" || len(got.Types) != 1 || got.Types[0].Type.Name != "electric" || got.SpeciesID() != 25 {
+		t.Fatalf("valor decodificado = %+v", got)
+	}
+	if entry.Validators != validators {
+		t.Fatalf("validadores = %+v, se esperaba %+v", entry.Validators, validators)
+	}
+	if entry.IsExpired() || time.Until(entry.ExpiresAt) > time.Minute {
+		t.Fatalf("vencimiento = %v", entry.ExpiresAt)
+	}
+
+	// Los bytes guardados se decodifican igual sin pasar por la cache tipada
+	item, found := cache.GetEntry(ctx, "pokemon:id:25")
+	if !found {
+		t.Fatal("GetEntry no encontro la clave")
+	}
+	decoded, err := decodeItem(mustEncode(t, item))
+	if err != nil {
+		t.Fatalf("decodeItem: %v", err)
+	}
+	if string(decoded.Data.([]byte)) != string(item.Data.([]byte)) || !decoded.StaleUntil.Equal(item.StaleUntil) || decoded.Validators != item.Validators {
+		t.Fatal("encodeItem/decodeItem no conservan el elemento")
+	}
+}
+
+func mustEncode(t *testing.T, item *entities.CacheItem) []byte {
+	t.Helper()
+
+	payload, err := encodeItem(item)
+	if err != nil {
+		t.Fatalf("encodeItem: %v", err)
+	}
+	return payload
+}
@@ -0,0 +1,261 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Error devuelto por el servidor en una respuesta "-ERR ..."
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+var errNilReply = errors.New("redis: respuesta nula")
+
+// RedisOptions configura la conexion con cualquier servidor que hable el
+// protocolo RESP de Redis
+type RedisOptions struct {
+	Addr        string
+	Password    string
+	DB          int
+	PoolSize    int
+	DialTimeout time.Duration
+	// Tope de cada comando, aunque el contexto no tenga plazo
+	Timeout time.Duration
+}
+
+// Conexion RESP con su lector y escritor
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// Cliente RESP minimo con un pool de conexiones reutilizables
+type redisClient struct {
+	options RedisOptions
+	pool    chan *redisConn
+}
+
+func newRedisClient(options RedisOptions) *redisClient {
+	if options.PoolSize <= 0 {
+		options.PoolSize = 10
+	}
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 3 * time.Second
+	}
+	return &redisClient{
+		options: options,
+		pool:    make(chan *redisConn, options.PoolSize),
+	}
+}
+
+// Do envia un comando y devuelve la respuesta: string, int64, []interface{}
+// o errNilReply si el servidor respondio nulo. El comando dura como maximo
+// options.Timeout o el plazo de ctx, y se corta si ctx se cancela
+func (c *redisClient) Do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(c.options.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.conn.SetDeadline(deadline)
+
+	// Cerrar la conexion desbloquea la lectura cuando se cancela el contexto
+	stop := context.AfterFunc(ctx, func() { conn.conn.Close() })
+	reply, err := conn.do(args...)
+	if !stop() {
+		// La conexion ya se cerro; el resultado no es confiable
+		return nil, ctx.Err()
+	}
+
+	var serverErr redisError
+	if err != nil && !errors.Is(err, errNilReply) && !errors.As(err, &serverErr) {
+		// Error de red o de protocolo: la conexion no se reutiliza
+		conn.conn.Close()
+		return nil, err
+	}
+
+	c.put(conn)
+	return reply, err
+}
+
+// Subscribe abre una conexion dedicada, se suscribe al canal y llama a
+// handler con cada mensaje. Bloquea hasta que la conexion falla o ctx termina
+func (c *redisClient) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.conn.Close()
+
+	// Cerrar la conexion desbloquea la lectura cuando se cancela el contexto
+	stop := context.AfterFunc(ctx, func() { conn.conn.Close() })
+	defer stop()
+
+	conn.conn.SetDeadline(time.Time{})
+	if err := conn.writeCommand("SUBSCRIBE", channel); err != nil {
+		return err
+	}
+
+	for {
+		reply, err := conn.readReply()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		// Los mensajes llegan como ["message", canal, contenido]
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 3 {
+			continue
+		}
+		if kind, _ := parts[0].(string); kind != "message" {
+			continue
+		}
+		if message, ok := parts[2].(string); ok {
+			handler(message)
+		}
+	}
+}
+
+func (c *redisClient) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-c.pool:
+		return conn, nil
+	default:
+		return c.dial(ctx)
+	}
+}
+
+func (c *redisClient) put(conn *redisConn) {
+	select {
+	case c.pool <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+// Abre una conexion y aplica autenticacion y base de datos
+func (c *redisClient) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: c.options.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.options.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar a redis: %w", err)
+	}
+
+	conn := &redisConn{
+		conn:   netConn,
+		reader: bufio.NewReader(netConn),
+		writer: bufio.NewWriter(netConn),
+	}
+	netConn.SetDeadline(time.Now().Add(c.options.DialTimeout))
+
+	if c.options.Password != "" {
+		if _, err := conn.do("AUTH", c.options.Password); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("no se pudo autenticar en redis: %w", err)
+		}
+	}
+	if c.options.DB != 0 {
+		if _, err := conn.do("SELECT", strconv.Itoa(c.options.DB)); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("no se pudo seleccionar la base de redis: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *redisConn) do(args ...string) (interface{}, error) {
+	if err := c.writeCommand(args...); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// Escribe un comando como arreglo de bulk strings
+func (c *redisConn) writeCommand(args ...string) error {
+	fmt.Fprintf(c.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.writer, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return c.writer.Flush()
+}
+
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: respuesta vacia")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: longitud no valida: %w", err)
+		}
+		if size < 0 {
+			return nil, errNilReply
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: longitud no valida: %w", err)
+		}
+		if count < 0 {
+			return nil, errNilReply
+		}
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := c.readReply()
+			if err != nil && !errors.Is(err, errNilReply) {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: tipo de respuesta desconocido: %q", line[0])
+	}
+}
+
+func (c *redisConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errors.New("redis: linea mal formada")
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,293 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Servidor RESP en memoria con los comandos que usa RedisCache, incluido
+// pub/sub para TieredCache
+type fakeRedis struct {
+	listener net.Listener
+
+	mutex       sync.Mutex
+	values      map[string]string
+	expires     map[string]time.Time
+	stalled     bool
+	subscribers map[string][]net.Conn
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("no se pudo abrir el servidor de prueba: %v", err)
+	}
+	server := &fakeRedis{
+		listener:    listener,
+		values:      make(map[string]string),
+		expires:     make(map[string]time.Time),
+		subscribers: make(map[string][]net.Conn),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go server.serve()
+	return server
+}
+
+func (s *fakeRedis) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Has indica si la clave existe y no vencio
+func (s *fakeRedis) Has(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.lookup(key)
+	return ok
+}
+
+// Put guarda una clave sin pasar por el protocolo
+func (s *fakeRedis) Put(key, value string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.values[key] = value
+}
+
+// Stall hace que el servidor lea los comandos sin responderlos, como un
+// Redis colgado
+func (s *fakeRedis) Stall(stalled bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stalled = stalled
+}
+
+// Subscribers devuelve cuantas conexiones escuchan el canal
+func (s *fakeRedis) Subscribers(channel string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.subscribers[channel])
+}
+
+// DropSubscribers cierra las conexiones suscritas, como un corte de red
+func (s *fakeRedis) DropSubscribers() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for channel, conns := range s.subscribers {
+		for _, conn := range conns {
+			conn.Close()
+		}
+		delete(s.subscribers, channel)
+	}
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	defer s.unsubscribe(conn)
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		stalled := s.stalled
+		s.mutex.Unlock()
+		if stalled {
+			continue
+		}
+		if strings.ToUpper(args[0]) == "SUBSCRIBE" {
+			s.subscribe(conn, args[1])
+			continue
+		}
+		if _, err := io.WriteString(conn, s.execute(args)); err != nil {
+			return
+		}
+	}
+}
+
+// Lee un comando enviado como arreglo de bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+	return args, nil
+}
+
+func (s *fakeRedis) execute(args []string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		value, ok := s.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "SET":
+		s.values[args[1]] = args[2]
+		delete(s.expires, args[1])
+		if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+			millis, err := strconv.Atoi(args[4])
+			if err != nil {
+				return "-ERR value is not an integer\r\n"
+			}
+			s.expires[args[1]] = time.Now().Add(time.Duration(millis) * time.Millisecond)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				delete(s.values, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "SCAN":
+		return s.scan(args)
+	case "PUBLISH":
+		return s.publish(args[1], args[2])
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+// Valor vigente de una clave; las vencidas se eliminan al leerlas
+func (s *fakeRedis) lookup(key string) (string, bool) {
+	if expires, ok := s.expires[key]; ok && time.Now().After(expires) {
+		delete(s.values, key)
+		delete(s.expires, key)
+	}
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// SCAN cursor MATCH patron COUNT n. El cursor es la posicion en las claves
+// ordenadas, suficiente para recorrer varias paginas
+func (s *fakeRedis) scan(args []string) string {
+	start, _ := strconv.Atoi(args[1])
+	pattern, count := "*", 10
+	for i := 2; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			count, _ = strconv.Atoi(args[i+1])
+		}
+	}
+
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		if _, ok := s.lookup(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	end := min(start+count, len(keys))
+	next := end
+	if end >= len(keys) {
+		next = 0
+	}
+
+	var matched []string
+	for _, key := range keys[min(start, len(keys)):end] {
+		if ok, _ := path.Match(pattern, key); ok {
+			matched = append(matched, key)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("*2\r\n")
+	b.WriteString(bulk(strconv.Itoa(next)))
+	fmt.Fprintf(&b, "*%d\r\n", len(matched))
+	for _, key := range matched {
+		b.WriteString(bulk(key))
+	}
+	return b.String()
+}
+
+// Confirma la suscripcion y registra la conexion. Las escrituras a los
+// suscriptores se hacen con el mutex tomado para no mezclar mensajes
+func (s *fakeRedis) subscribe(conn net.Conn, channel string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	io.WriteString(conn, "*3\r\n"+bulk("subscribe")+bulk(channel)+":1\r\n")
+	s.subscribers[channel] = append(s.subscribers[channel], conn)
+}
+
+func (s *fakeRedis) unsubscribe(conn net.Conn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for channel, conns := range s.subscribers {
+		s.subscribers[channel] = slices.DeleteFunc(conns, func(c net.Conn) bool { return c == conn })
+	}
+}
+
+// Envia el mensaje a cada suscriptor del canal; requiere el mutex
+func (s *fakeRedis) publish(channel, message string) string {
+	payload := "*3\r\n" + bulk("message") + bulk(channel) + bulk(message)
+	for _, conn := range s.subscribers[channel] {
+		io.WriteString(conn, payload)
+	}
+	return fmt.Sprintf(":%d\r\n", len(s.subscribers[channel]))
+}
+
+func bulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
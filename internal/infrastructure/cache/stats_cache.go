@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+// Motivo por el que un backend elimino una clave por su cuenta
+type removalReason int
+
+const (
+	removedEvicted removalReason = iota
+	removedExpired
+)
+
+// Funcion que los backends locales llaman al desalojar o expirar una clave
+type removalHook func(key string, reason removalReason)
+
+func (h removalHook) notify(key string, reason removalReason) {
+	if h != nil {
+		h(key, reason)
+	}
+}
+
+// Lo implementan los backends que pueden informar desalojos y expiraciones
+type removalNotifier interface {
+	setRemovalHook(hook removalHook)
+}
+
+// StatsCache envuelve un CacheService y cuenta aciertos, fallos, desalojos y
+// expiraciones por espacio de nombres. Redis elimina las claves por su cuenta,
+// por lo que con ese backend solo se cuentan aciertos y fallos
+type StatsCache struct {
+	services.CacheService
+	mutex sync.Mutex
+	stats map[string]*entities.CacheStats
+}
+
+func NewStatsCache(cacheService services.CacheService) *StatsCache {
+	cache := &StatsCache{
+		CacheService: cacheService,
+		stats:        make(map[string]*entities.CacheStats),
+	}
+
+	if notifier, ok := cacheService.(removalNotifier); ok {
+		notifier.setRemovalHook(cache.recordRemoval)
+	}
+
+	return cache
+}
+
+func (c *StatsCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	value, found := c.CacheService.Get(ctx, key)
+	c.record(key, func(s *entities.CacheStats) {
+		if found {
+			s.Hits++
+		} else {
+			s.Misses++
+		}
+	})
+	return value, found
+}
+
+func (c *StatsCache) GetEntry(ctx context.Context, key string) (*entities.CacheItem, bool) {
+	item, found := c.CacheService.GetEntry(ctx, key)
+	c.record(key, func(s *entities.CacheStats) {
+		switch {
+		case !found:
+			s.Misses++
+		case item.IsExpired():
+			s.StaleHits++
+		default:
+			s.Hits++
+		}
+	})
+	return item, found
+}
+
+// CacheStats devuelve una copia de los contadores por espacio de nombres
+func (c *StatsCache) CacheStats() map[string]entities.CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := make(map[string]entities.CacheStats, len(c.stats))
+	for namespace, s := range c.stats {
+		stats[namespace] = *s
+	}
+	return stats
+}
+
+// Metrics expone los contadores en /metrics
+func (c *StatsCache) Metrics() map[string]interface{} {
+	stats := c.CacheStats()
+	metrics := make(map[string]interface{}, len(stats))
+	for namespace, s := range stats {
+		metrics[namespace] = s
+	}
+	return metrics
+}
+
+func (c *StatsCache) recordRemoval(key string, reason removalReason) {
+	c.record(key, func(s *entities.CacheStats) {
+		if reason == removedEvicted {
+			s.Evictions++
+		} else {
+			s.Expirations++
+		}
+	})
+}
+
+func (c *StatsCache) record(key string, update func(s *entities.CacheStats)) {
+	namespace := keyNamespace(key)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, found := c.stats[namespace]
+	if !found {
+		s = &entities.CacheStats{}
+		c.stats[namespace] = s
+	}
+	update(s)
+}
+
+// El espacio de nombres son los dos primeros segmentos de la clave
+func keyNamespace(key string) string {
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) < 2 {
+		return key
+	}
+	return parts[0] + ":" + parts[1]
+}
+
+func filterKeys(keys []string, prefix string) []string {
+	filtered := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
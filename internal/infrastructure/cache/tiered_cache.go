@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+// Operaciones que viajan por el canal de invalidaciones
+const (
+	invalidateSet    = "set"
+	invalidateDelete = "del"
+	invalidateClear  = "clear"
+)
+
+// TieredCache lee primero del LRU local y recurre a la cache compartida en
+// caso de fallo. Las escrituras van a ambas y cada cambio se publica para que
+// las demas replicas descarten su copia local
+type TieredCache struct {
+	local      *LRUCache
+	shared     *RedisCache
+	channel    string
+	instanceID string
+	cancel     context.CancelFunc
+}
+
+// NewTieredCache combina ambas caches y empieza a escuchar las invalidaciones
+// publicadas por otras replicas en channel
+func NewTieredCache(local *LRUCache, shared *RedisCache, channel string) *TieredCache {
+	ctx, cancel := context.WithCancel(context.Background())
+	cache := &TieredCache{
+		local:      local,
+		shared:     shared,
+		channel:    channel,
+		instanceID: newInstanceID(),
+		cancel:     cancel,
+	}
+
+	go cache.listenInvalidations(ctx)
+
+	return cache
+}
+
+func (c *TieredCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	item, found := c.GetEntry(ctx, key)
+	if !found || item.IsExpired() {
+		return nil, false
+	}
+	return item.Data, true
+}
+
+func (c *TieredCache) GetEntry(ctx context.Context, key string) (*entities.CacheItem, bool) {
+	localItem, localFound := c.local.GetEntry(ctx, key)
+	if localFound && !localItem.IsExpired() {
+		return localItem, true
+	}
+
+	// Otra replica pudo haber refrescado el elemento en la cache compartida
+	sharedItem, sharedFound := c.shared.GetEntry(ctx, key)
+	if !sharedFound {
+		return localItem, localFound
+	}
+	if localFound && !sharedItem.ExpiresAt.After(localItem.ExpiresAt) {
+		return localItem, true
+	}
+
+	c.local.setItem(key, sharedItem)
+	return sharedItem, true
+}
+
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, opts ...services.SetOption) error {
+	item := newItem(value, c.local.ttl, c.local.staleTTL, opts)
+
+	// Ambas capas comparten los mismos vencimientos
+	c.local.setItem(key, item)
+	if err := c.shared.setItem(ctx, key, item); err != nil {
+		return err
+	}
+	// Las demas replicas descartan su copia y leen la nueva de la compartida
+	return c.shared.publish(ctx, c.channel, c.message(invalidateSet, key))
+}
+
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	c.local.Delete(ctx, key)
+	if err := c.shared.Delete(ctx, key); err != nil {
+		return err
+	}
+	return c.shared.publish(ctx, c.channel, c.message(invalidateDelete, key))
+}
+
+func (c *TieredCache) Clear(ctx context.Context) error {
+	c.local.Clear(ctx)
+	if err := c.shared.Clear(ctx); err != nil {
+		return err
+	}
+	return c.shared.publish(ctx, c.channel, c.message(invalidateClear, ""))
+}
+
+// Keys devuelve las claves de la cache compartida, que contiene a las locales
+func (c *TieredCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	return c.shared.Keys(ctx, prefix)
+}
+
+// Las estadisticas de desalojo corresponden a la copia local
+func (c *TieredCache) setRemovalHook(hook removalHook) {
+	c.local.setRemovalHook(hook)
+}
+
+// Metrics expone la ocupacion de la copia local
+func (c *TieredCache) Metrics() map[string]interface{} {
+	return c.local.Metrics()
+}
+
+// Close deja de escuchar invalidaciones
+func (c *TieredCache) Close() {
+	c.cancel()
+}
+
+// Formato del mensaje: instancia|operacion|clave
+func (c *TieredCache) message(operation, key string) string {
+	return c.instanceID + "|" + operation + "|" + key
+}
+
+// Mantiene la suscripcion abierta y se reconecta con espera creciente si se pierde
+func (c *TieredCache) listenInvalidations(ctx context.Context) {
+	const maxBackoff = 30 * time.Second
+	backoff := time.Second
+
+	for {
+		start := time.Now()
+		err := c.shared.subscribe(ctx, c.channel, c.applyInvalidation)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Mientras no hay suscripcion se pueden perder invalidaciones, por lo
+		// que se descarta la copia local al reconectar
+		c.local.Clear(ctx)
+
+		if time.Since(start) > maxBackoff {
+			backoff = time.Second
+		}
+		log.Printf("suscripcion a invalidaciones de cache perdida, reintento en %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (c *TieredCache) applyInvalidation(message string) {
+	parts := strings.SplitN(message, "|", 3)
+	if len(parts) != 3 {
+		log.Printf("mensaje de invalidacion no valido: %q", message)
+		return
+	}
+
+	// Los cambios propios ya se aplicaron localmente
+	if parts[0] == c.instanceID {
+		return
+	}
+
+	ctx := context.Background()
+	switch parts[1] {
+	case invalidateSet, invalidateDelete:
+		c.local.Delete(ctx, parts[2])
+	case invalidateClear:
+		c.local.Clear(ctx)
+	}
+}
+
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const testChannel = "test:invalidations"
+
+// Dos replicas con su propio LRU sobre el mismo servidor
+func newTieredPair(t *testing.T) (*TieredCache, *TieredCache, *fakeRedis) {
+	t.Helper()
+
+	server := newFakeRedis(t)
+	replica := func() *TieredCache {
+		shared, err := NewRedisCache(RedisOptions{Addr: server.Addr()}, "test:", time.Minute, time.Minute)
+		if err != nil {
+			t.Fatalf("NewRedisCache: %v", err)
+		}
+		local, err := NewLRUCache(100, 0, time.Minute, time.Minute)
+		if err != nil {
+			t.Fatalf("NewLRUCache: %v", err)
+		}
+		cache := NewTieredCache(local, shared, testChannel)
+		t.Cleanup(cache.Close)
+		return cache
+	}
+
+	a, b := replica(), replica()
+	waitFor(t, 2*time.Second, "las replicas no se suscribieron", func() bool {
+		return server.Subscribers(testChannel) == 2
+	})
+	return a, b, server
+}
+
+func waitFor(t *testing.T, timeout time.Duration, message string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func hasLocal(cache *TieredCache, key string) bool {
+	_, found := cache.local.GetEntry(context.Background(), key)
+	return found
+}
+
+// Lee la clave en la replica para que quede una copia en su LRU
+func warmLocal(t *testing.T, cache *TieredCache, key, want string) {
+	t.Helper()
+
+	value, found := cache.Get(context.Background(), key)
+	if !found || string(value.([]byte)) != want {
+		t.Fatalf("Get(%s) = %v, %v; se esperaba %s", key, value, found, want)
+	}
+	if !hasLocal(cache, key) {
+		t.Fatalf("Get(%s) no copio el valor al LRU local", key)
+	}
+}
+
+func TestTieredCacheSetInvalidatesOtherReplicas(t *testing.T) {
+	a, b, _ := newTieredPair(t)
+	ctx := context.Background()
+
+	if err := a.Set(ctx, "pokemon:id:25", []byte("v1")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	warmLocal(t, b, "pokemon:id:25", "v1")
+
+	if err := a.Set(ctx, "pokemon:id:25", []byte("v2")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	waitFor(t, time.Second, "Set no invalido la copia de la otra replica", func() bool {
+		return !hasLocal(b, "pokemon:id:25")
+	})
+	warmLocal(t, b, "pokemon:id:25", "v2")
+}
+
+func TestTieredCacheDeleteAndClearInvalidateOtherReplicas(t *testing.T) {
+	a, b, _ := newTieredPair(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"pokemon:id:1", "pokemon:id:2", "pokemon:id:3"} {
+		if err := a.Set(ctx, key, []byte("{}")); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+		warmLocal(t, b, key, "{}")
+	}
+
+	if err := a.Delete(ctx, "pokemon:id:1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	waitFor(t, time.Second, "Delete no invalido la copia de la otra replica", func() bool {
+		return !hasLocal(b, "pokemon:id:1")
+	})
+	if !hasLocal(b, "pokemon:id:2") {
+		t.Fatal("Delete invalido una clave distinta")
+	}
+
+	if err := a.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	waitFor(t, time.Second, "Clear no vacio el LRU de la otra replica", func() bool {
+		return !hasLocal(b, "pokemon:id:2") && !hasLocal(b, "pokemon:id:3")
+	})
+}
+
+func TestTieredCacheIgnoresOwnMessages(t *testing.T) {
+	a, b, _ := newTieredPair(t)
+	ctx := context.Background()
+
+	if err := a.Set(ctx, "pokemon:id:25", []byte("v1")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	warmLocal(t, b, "pokemon:id:25", "v1")
+
+	// El mensaje de a llega a ambas replicas: b descarta su copia y a la conserva
+	a.applyInvalidation(a.message(invalidateSet, "pokemon:id:25"))
+	if !hasLocal(a, "pokemon:id:25") {
+		t.Fatal("la replica descarto su copia por su propio mensaje")
+	}
+	b.applyInvalidation(a.message(invalidateSet, "pokemon:id:25"))
+	if hasLocal(b, "pokemon:id:25") {
+		t.Fatal("la replica no descarto su copia por el mensaje de otra")
+	}
+
+	// De punta a punta: tras la publicacion de a, b ya la aplico y a sigue igual
+	warmLocal(t, b, "pokemon:id:25", "v1")
+	if err := a.Set(ctx, "pokemon:id:25", []byte("v2")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	waitFor(t, time.Second, "Set no invalido la copia de la otra replica", func() bool {
+		return !hasLocal(b, "pokemon:id:25")
+	})
+	if !hasLocal(a, "pokemon:id:25") {
+		t.Fatal("la replica que escribio perdio su copia local")
+	}
+}
+
+func TestTieredCacheResubscribesAfterConnectionLoss(t *testing.T) {
+	a, b, server := newTieredPair(t)
+	ctx := context.Background()
+
+	if err := a.Set(ctx, "pokemon:id:25", []byte("v1")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	warmLocal(t, b, "pokemon:id:25", "v1")
+
+	// Sin suscripcion se pudieron perder invalidaciones: se vacia el LRU
+	server.DropSubscribers()
+	waitFor(t, time.Second, "la replica no vacio su LRU al perder la suscripcion", func() bool {
+		return !hasLocal(b, "pokemon:id:25")
+	})
+
+	waitFor(t, 3*time.Second, "las replicas no se volvieron a suscribir", func() bool {
+		return server.Subscribers(testChannel) == 2
+	})
+	warmLocal(t, b, "pokemon:id:25", "v1")
+	if err := a.Set(ctx, "pokemon:id:25", []byte("v2")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	waitFor(t, time.Second, "la invalidacion no llego tras reconectar", func() bool {
+		return !hasLocal(b, "pokemon:id:25")
+	})
+}
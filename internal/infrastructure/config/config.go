@@ -0,0 +1,279 @@
+package config
+
+import (
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Configuracion de la aplicacion, leida de variables de entorno con valores
+// por defecto pensados para desarrollo local
+type Config struct {
+	ServerPort         string
+	PokeAPIURL         string
+	CacheBackend       string
+	CacheDir           string
+	CacheSize          int
+	CacheMaxBytes      int64
+	CacheTTL           time.Duration
+	CacheStaleTTL      time.Duration
+	CacheTTLPolicies   map[string]time.Duration
+	CacheTTLJitter     float64
+	SearchIndexRefresh time.Duration
+	CursorSecret       string
+	CursorTTL          time.Duration
+	PublicBaseURL      string
+	Redis              RedisConfig
+	AdminToken         string
+	Warmup             WarmupConfig
+	Retry              RetryConfig
+	Breaker            BreakerConfig
+	RateLimit          RateLimitConfig
+}
+
+// Conexion al servidor compatible con Redis cuando CACHE_BACKEND=redis o tiered
+type RedisConfig struct {
+	Addr      string
+	Password  string
+	DB        int
+	PoolSize  int
+	Timeout   time.Duration
+	KeyPrefix string
+	// Canal pub/sub por el que las replicas se avisan de invalidaciones
+	InvalidationChannel string
+}
+
+// Precalentamiento de la cache al iniciar
+type WarmupConfig struct {
+	Enabled     bool
+	Pokemon     int
+	Concurrency int
+}
+
+// Reintentos de las solicitudes a PokeAPI
+type RetryConfig struct {
+	MaxAttempts    int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	AttemptTimeout time.Duration
+	MaxRetryAfter  time.Duration
+}
+
+// Circuit breaker de las solicitudes a PokeAPI
+type BreakerConfig struct {
+	Window           time.Duration
+	MinRequests      int
+	FailureRatio     float64
+	OpenTimeout      time.Duration
+	HalfOpenRequests int
+}
+
+// Limite de solicitudes salientes a PokeAPI
+type RateLimitConfig struct {
+	// Solicitudes por segundo; 0 desactiva el limite
+	Rate    float64
+	Burst   int
+	MaxWait time.Duration
+}
+
+func Load() *Config {
+	return &Config{
+		ServerPort: getEnv("SERVER_PORT", ":8080"),
+		PokeAPIURL: getEnv("POKEAPI_URL", "https://pokeapi.co/api/v2"),
+		// memory (LRU local), redis (compartida entre replicas), tiered (ambas)
+		// o disk (persistente entre reinicios)
+		CacheBackend: getEnv("CACHE_BACKEND", "memory"),
+		CacheDir:     getEnv("CACHE_DIR", "./data/cache"),
+		CacheSize:    getEnvInt("CACHE_SIZE", 1000),
+		// Presupuesto aproximado de memoria de la cache local (0 = sin limite)
+		CacheMaxBytes: getEnvBytes("CACHE_MAX_BYTES", 64<<20),
+		CacheTTL:      getEnvDuration("CACHE_TTL", 15*time.Minute),
+		// Tiempo extra durante el cual se sirven datos vencidos si PokeAPI falla
+		CacheStaleTTL: getEnvDuration("CACHE_STALE_TTL", time.Hour),
+		// Los detalles casi nunca cambian; listados y busquedas deben ser mas frescos
+		CacheTTLPolicies: getEnvTTLPolicies("CACHE_TTL_POLICIES", map[string]time.Duration{
+			"pokemon:id":        24 * time.Hour,
+			"pokemon:name":      24 * time.Hour,
+			"pokemon:species":   24 * time.Hour,
+			"pokemon:evolution": 24 * time.Hour,
+			"pokemon:list":      5 * time.Minute,
+			"pokemon:search":    5 * time.Minute,
+		}),
+		CacheTTLJitter: getEnvFloat("CACHE_TTL_JITTER", 0.1),
+		// El listado de nombres cambia solo con nuevas generaciones
+		SearchIndexRefresh: getEnvDuration("SEARCH_INDEX_REFRESH", 6*time.Hour),
+		CursorSecret:       getEnv("CURSOR_SECRET", ""),
+		CursorTTL:          getEnvDuration("CURSOR_TTL", 24*time.Hour),
+		// Base de los enlaces de paginacion; vacia los deja relativos
+		PublicBaseURL: getEnvURL("PUBLIC_BASE_URL", ""),
+		Redis: RedisConfig{
+			Addr:                getEnv("REDIS_ADDR", "localhost:6379"),
+			Password:            getEnv("REDIS_PASSWORD", ""),
+			DB:                  getEnvInt("REDIS_DB", 0),
+			PoolSize:            getEnvInt("REDIS_POOL_SIZE", 10),
+			Timeout:             getEnvDuration("REDIS_TIMEOUT", 3*time.Second),
+			KeyPrefix:           getEnv("REDIS_KEY_PREFIX", "pokeapi:"),
+			InvalidationChannel: getEnv("REDIS_INVALIDATION_CHANNEL", "pokeapi:invalidations"),
+		},
+		// Sin token las rutas /admin quedan deshabilitadas
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+		Retry: RetryConfig{
+			MaxAttempts:    getEnvInt("RETRY_MAX_ATTEMPTS", 3),
+			BaseDelay:      getEnvDuration("RETRY_BASE_DELAY", 200*time.Millisecond),
+			MaxDelay:       getEnvDuration("RETRY_MAX_DELAY", 5*time.Second),
+			AttemptTimeout: getEnvDuration("RETRY_ATTEMPT_TIMEOUT", 10*time.Second),
+			MaxRetryAfter:  getEnvDuration("RETRY_MAX_RETRY_AFTER", 10*time.Second),
+		},
+		Breaker: BreakerConfig{
+			Window:           getEnvDuration("BREAKER_WINDOW", 30*time.Second),
+			MinRequests:      getEnvInt("BREAKER_MIN_REQUESTS", 10),
+			FailureRatio:     getEnvFloat("BREAKER_FAILURE_RATIO", 0.5),
+			OpenTimeout:      getEnvDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second),
+			HalfOpenRequests: getEnvInt("BREAKER_HALF_OPEN_REQUESTS", 3),
+		},
+		RateLimit: RateLimitConfig{
+			Rate:    getEnvFloat("POKEAPI_RATE_LIMIT", 10),
+			Burst:   getEnvInt("POKEAPI_RATE_BURST", 20),
+			MaxWait: getEnvDuration("POKEAPI_RATE_MAX_WAIT", 10*time.Second),
+		},
+		Warmup: WarmupConfig{
+			Enabled:     getEnvBool("WARMUP_ENABLED", true),
+			Pokemon:     getEnvInt("WARMUP_POKEMON", 151),
+			Concurrency: getEnvInt("WARMUP_CONCURRENCY", 4),
+		},
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Solo acepta URL absolutas http o https, por ejemplo https://api.example.com
+func getEnvURL(key, fallback string) string {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		log.Printf("valor no valido para %s: %q, usando %q", key, value, fallback)
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("valor no valido para %s: %q, usando %d", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("valor no valido para %s: %q, usando %t", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// Acepta bytes o un sufijo KB, MB o GB (base 1024), por ejemplo 64MB
+func getEnvBytes(key string, fallback int64) int64 {
+	value := strings.ToUpper(strings.TrimSpace(getEnv(key, "")))
+	if value == "" {
+		return fallback
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, multiplier = strings.TrimSpace(number), unit.size
+			break
+		}
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		log.Printf("valor no valido para %s: %q, usando %d", key, getEnv(key, ""), fallback)
+		return fallback
+	}
+	return parsed * multiplier
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("valor no valido para %s: %q, usando %g", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// Lee politicas "prefijo=duracion" separadas por coma, por ejemplo
+// "pokemon:id=48h,pokemon:search=1m". Se combinan con las de defaults
+func getEnvTTLPolicies(key string, defaults map[string]time.Duration) map[string]time.Duration {
+	policies := make(map[string]time.Duration, len(defaults))
+	for prefix, ttl := range defaults {
+		policies[prefix] = ttl
+	}
+
+	value := getEnv(key, "")
+	if value == "" {
+		return policies
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		prefix, rawTTL, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		ttl, err := time.ParseDuration(strings.TrimSpace(rawTTL))
+		if !ok || prefix == "" || err != nil || ttl <= 0 {
+			log.Printf("politica de TTL no valida en %s: %q, se ignora", key, entry)
+			continue
+		}
+		policies[prefix] = ttl
+	}
+	return policies
+}
+
+// Las duraciones deben ser positivas; cero o negativas usan fallback
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("valor no valido para %s: %q, usando %s", key, value, fallback)
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import "github.com/gerardstrujills/backend/internal/domain/entities"
+
+// Respuesta de /evolution-chain/{id} en PokeAPI
+type evolutionChainResponse struct {
+	ID    int               `json:"id"`
+	Chain chainLinkResponse `json:"chain"`
+}
+
+type chainLinkResponse struct {
+	IsBaby           bool                      `json:"is_baby"`
+	Species          entities.NamedResource    `json:"species"`
+	EvolutionDetails []evolutionDetailResponse `json:"evolution_details"`
+	EvolvesTo        []chainLinkResponse       `json:"evolves_to"`
+}
+
+type evolutionDetailResponse struct {
+	Trigger               *entities.NamedResource `json:"trigger"`
+	MinLevel              *int                    `json:"min_level"`
+	Item                  *entities.NamedResource `json:"item"`
+	HeldItem              *entities.NamedResource `json:"held_item"`
+	MinHappiness          *int                    `json:"min_happiness"`
+	MinAffection          *int                    `json:"min_affection"`
+	MinBeauty             *int                    `json:"min_beauty"`
+	TimeOfDay             string                  `json:"time_of_day"`
+	KnownMove             *entities.NamedResource `json:"known_move"`
+	KnownMoveType         *entities.NamedResource `json:"known_move_type"`
+	Location              *entities.NamedResource `json:"location"`
+	Gender                *int                    `json:"gender"`
+	PartySpecies          *entities.NamedResource `json:"party_species"`
+	TradeSpecies          *entities.NamedResource `json:"trade_species"`
+	NeedsOverworldRain    bool                    `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool                    `json:"turn_upside_down"`
+	RelativePhysicalStats *int                    `json:"relative_physical_stats"`
+}
+
+func (e *evolutionChainResponse) toEntity() *entities.EvolutionChain {
+	return &entities.EvolutionChain{
+		ID:    e.ID,
+		Chain: e.Chain.toEntity(),
+	}
+}
+
+// Convierte recursivamente cada eslabon de la cadena
+func (l *chainLinkResponse) toEntity() entities.EvolutionNode {
+	node := entities.EvolutionNode{
+		SpeciesID:  entities.IDFromURL(l.Species.URL),
+		Species:    l.Species.Name,
+		IsBaby:     l.IsBaby,
+		Conditions: make([]entities.EvolutionCondition, 0, len(l.EvolutionDetails)),
+		EvolvesTo:  make([]entities.EvolutionNode, 0, len(l.EvolvesTo)),
+	}
+
+	for _, detail := range l.EvolutionDetails {
+		node.Conditions = append(node.Conditions, detail.toEntity())
+	}
+	for i := range l.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, l.EvolvesTo[i].toEntity())
+	}
+
+	return node
+}
+
+func (d *evolutionDetailResponse) toEntity() entities.EvolutionCondition {
+	return entities.EvolutionCondition{
+		Trigger:               resourceName(d.Trigger),
+		MinLevel:              d.MinLevel,
+		Item:                  resourceName(d.Item),
+		HeldItem:              resourceName(d.HeldItem),
+		MinHappiness:          d.MinHappiness,
+		MinAffection:          d.MinAffection,
+		MinBeauty:             d.MinBeauty,
+		TimeOfDay:             d.TimeOfDay,
+		KnownMove:             resourceName(d.KnownMove),
+		KnownMoveType:         resourceName(d.KnownMoveType),
+		Location:              resourceName(d.Location),
+		Gender:                d.Gender,
+		PartySpecies:          resourceName(d.PartySpecies),
+		TradeSpecies:          resourceName(d.TradeSpecies),
+		NeedsOverworldRain:    d.NeedsOverworldRain,
+		TurnUpsideDown:        d.TurnUpsideDown,
+		RelativePhysicalStats: d.RelativePhysicalStats,
+	}
+}
+
+func resourceName(r *entities.NamedResource) string {
+	if r == nil {
+		return ""
+	}
+	return r.Name
+}
@@ -3,27 +3,47 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
-
+	"sync/atomic"
 	"time"
 
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
 	"github.com/gerardstrujills/backend/internal/domain/entities"
+	domainrepo "github.com/gerardstrujills/backend/internal/domain/repositories"
+	"github.com/gerardstrujills/backend/internal/infrastructure/resilience"
 )
 
+// Tope de cada solicitud HTTP por si el intento no tiene plazo propio (por
+// ejemplo una recarga en segundo plano con AttemptTimeout en cero)
+const clientTimeout = 30 * time.Second
+
 type PokemonAPIRepository struct {
 	baseURL    string
 	httpClient *http.Client
+	retry      RetryPolicy
+	limiter    *resilience.RateLimiter
+
+	conditional atomic.Int64
+	notModified atomic.Int64
 }
 
-func NewPokemonAPIRepository(baseURL string) *PokemonAPIRepository {
+// NewPokemonAPIRepository crea el repositorio. El tiempo de cada intento lo
+// limita retry.AttemptTimeout dentro del plazo del contexto de la solicitud,
+// y nunca supera clientTimeout salvo que AttemptTimeout sea mayor.
+// transport permite envolver el cliente HTTP (por ejemplo con un circuit
+// breaker); nil usa http.DefaultTransport. limiter reparte los turnos de las
+// solicitudes salientes, incluidos los reintentos; nil no limita
+func NewPokemonAPIRepository(baseURL string, retry RetryPolicy, transport http.RoundTripper, limiter *resilience.RateLimiter) *PokemonAPIRepository {
 	return &PokemonAPIRepository{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:    baseURL,
+		httpClient: &http.Client{Transport: transport, Timeout: max(clientTimeout, retry.AttemptTimeout)},
+		retry:      retry,
+		limiter:    limiter,
 	}
 }
 
@@ -37,123 +57,200 @@ func (r *PokemonAPIRepository) GetByName(ctx context.Context, name string) (*ent
 	return r.fetchPokemon(ctx, url)
 }
 
-func (r *PokemonAPIRepository) GetList(ctx context.Context, limit, offset int) (*entities.PokemonList, error) {
-	url := fmt.Sprintf("%s/pokemon?limit=%d&offset=%d", r.baseURL, limit, offset)
+func (r *PokemonAPIRepository) GetSpeciesByID(ctx context.Context, id int) (*entities.Species, error) {
+	url := fmt.Sprintf("%s/pokemon-species/%d", r.baseURL, id)
+	return r.fetchSpecies(ctx, url)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("no se pudo crear la solicitud: %w", err)
-	}
+func (r *PokemonAPIRepository) GetSpeciesByName(ctx context.Context, name string) (*entities.Species, error) {
+	url := fmt.Sprintf("%s/pokemon-species/%s", r.baseURL, strings.ToLower(name))
+	return r.fetchSpecies(ctx, url)
+}
 
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("no se pudo obtener la lista de pokemon: %w", err)
-	}
-	defer resp.Body.Close()
+func (r *PokemonAPIRepository) GetEvolutionChain(ctx context.Context, id int) (*entities.EvolutionChain, error) {
+	url := fmt.Sprintf("%s/evolution-chain/%d", r.baseURL, id)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API return status %d", resp.StatusCode)
+	var chain evolutionChainResponse
+	if err := r.getJSON(ctx, url, "Cadena evolutiva no encontrada", &chain); err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("no se pudo leer el cuerpo de la respuesta: %w", err)
+	return chain.toEntity(), nil
+}
+
+func (r *PokemonAPIRepository) GetType(ctx context.Context, name string) (*entities.PokemonType, error) {
+	url := fmt.Sprintf("%s/type/%s", r.baseURL, strings.ToLower(name))
+
+	var pokemonType typeResponse
+	if err := r.getJSON(ctx, url, "Tipo no encontrado", &pokemonType); err != nil {
+		return nil, err
 	}
 
+	return pokemonType.toEntity(), nil
+}
+
+func (r *PokemonAPIRepository) GetList(ctx context.Context, limit, offset int) (*entities.PokemonList, error) {
+	url := fmt.Sprintf("%s/pokemon?limit=%d&offset=%d", r.baseURL, limit, offset)
+
 	var pokemonList entities.PokemonList
-	if err := json.Unmarshal(body, &pokemonList); err != nil {
-		return nil, fmt.Errorf("no se pudo serializar la lista de pokemon: %w", err)
+	if err := r.getJSON(ctx, url, "Lista de Pokemon no encontrada", &pokemonList); err != nil {
+		return nil, err
 	}
 
 	return &pokemonList, nil
 }
 
-func (r *PokemonAPIRepository) SearchByTitle(ctx context.Context, title string, limit, offset int) ([]*entities.Pokemon, error) {
-	// Cache inteligente && busqueda incremental
-	searchTerm := strings.ToLower(title)
-
-	// Validar lista pequeña
-	initialLimit := 100
-	pokemonList, err := r.GetList(ctx, initialLimit, 0)
-	if err != nil {
+func (r *PokemonAPIRepository) fetchPokemon(ctx context.Context, url string) (*entities.Pokemon, error) {
+	var pokemon entities.Pokemon
+	if err := r.getJSON(ctx, url, "Pokemon no encontrado", &pokemon); err != nil {
 		return nil, err
 	}
 
-	var results []*entities.Pokemon
-	var candidates []string
+	return &pokemon, nil
+}
 
-	// Filtrar candidatos por nombre
-	for _, pokemonResult := range pokemonList.Results {
-		if strings.Contains(strings.ToLower(pokemonResult.Name), searchTerm) {
-			candidates = append(candidates, pokemonResult.Name)
-		}
+func (r *PokemonAPIRepository) fetchSpecies(ctx context.Context, url string) (*entities.Species, error) {
+	var species speciesResponse
+	if err := r.getJSON(ctx, url, "Especie de Pokemon no encontrada", &species); err != nil {
+		return nil, err
 	}
 
-	// Si no hay suficientes resultados, expandir busqueda
-	if len(candidates) < limit+offset && pokemonList.Count > initialLimit {
-		// balance entre eficiencia y cobertura
-		expandedList, err := r.GetList(ctx, 500, 0)
-		if err == nil {
-			candidates = candidates[:0] // Reset
-			for _, pokemonResult := range expandedList.Results {
-				if strings.Contains(strings.ToLower(pokemonResult.Name), searchTerm) {
-					candidates = append(candidates, pokemonResult.Name)
-				}
-			}
-		}
-	}
+	return species.toEntity(), nil
+}
 
-	// Aplicar paginación a candidatos
-	start := offset
-	end := offset + limit
-	if start >= len(candidates) {
-		return results, nil
+// Metrics expone cuantas solicitudes condicionales se hicieron y cuantas
+// respondieron 304 sin volver a descargar el dato
+func (r *PokemonAPIRepository) Metrics() map[string]interface{} {
+	return map[string]interface{}{
+		"conditional_requests": r.conditional.Load(),
+		"not_modified":         r.notModified.Load(),
 	}
-	if end > len(candidates) {
-		end = len(candidates)
+}
+
+// getJSON hace un GET con reintentos y deserializa la respuesta en out. Si el
+// contexto trae una revalidacion, la solicitud es condicional y un 304 se
+// devuelve como domainrepo.ErrNotModified
+func (r *PokemonAPIRepository) getJSON(ctx context.Context, url, notFoundMessage string, out interface{}) error {
+	revalidation, _ := domainrepo.RevalidationFrom(ctx)
+
+	var body []byte
+	err := r.retry.do(ctx, r.limiter.Wait, func(ctx context.Context) (time.Duration, error) {
+		var (
+			wait time.Duration
+			err  error
+		)
+		body, wait, err = r.get(ctx, url, notFoundMessage, revalidation)
+		return wait, err
+	})
+	if err != nil {
+		return err
 	}
 
-	// Obtener detalles solo de los Pokemon necesarios
-	for i := start; i < end; i++ {
-		pokemon, err := r.GetByName(ctx, candidates[i])
-		if err != nil {
-			continue // Skip errores individuales
-		}
-		results = append(results, pokemon)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("no se pudo deserializar la respuesta de PokeAPI: %w", err)
 	}
 
-	return results, nil
+	return nil
 }
 
-func (r *PokemonAPIRepository) fetchPokemon(ctx context.Context, url string) (*entities.Pokemon, error) {
+// Un intento: devuelve el cuerpo, o el error y el Retry-After de la respuesta
+func (r *PokemonAPIRepository) get(ctx context.Context, url, notFoundMessage string, revalidation *domainrepo.Revalidation) ([]byte, time.Duration, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo crear la solicitud: %w", err)
+		return nil, 0, fmt.Errorf("no se pudo crear la solicitud: %w", err)
+	}
+
+	var cached entities.Validators
+	if revalidation != nil {
+		cached = revalidation.Cached()
+	}
+	if !cached.IsZero() {
+		setConditionalHeaders(req, cached)
+		r.conditional.Add(1)
 	}
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo obtener el pokemon: %w", err)
+		return nil, 0, classifyTransportError("no se pudo consultar PokeAPI", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("pokemon no encontrado")
+	// El dato en cache sigue vigente; el 304 puede omitir los validadores
+	if resp.StatusCode == http.StatusNotModified && !cached.IsZero() {
+		r.notModified.Add(1)
+		validators := validatorsOf(resp)
+		if validators.IsZero() {
+			validators = cached
+		}
+		revalidation.Receive(validators)
+		return nil, 0, domainrepo.ErrNotModified
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API return status %d", resp.StatusCode)
+	if err := checkStatus(resp, notFoundMessage); err != nil {
+		return nil, retryAfter(resp), err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo leer el cuerpo de la respuesta: %w", err)
+		return nil, 0, classifyTransportError("no se pudo leer el cuerpo de la respuesta", err)
 	}
 
-	var pokemon entities.Pokemon
-	if err := json.Unmarshal(body, &pokemon); err != nil {
-		return nil, fmt.Errorf("no se pudo serializar a los pokemon: %w", err)
+	if revalidation != nil {
+		revalidation.Receive(validatorsOf(resp))
 	}
 
-	return &pokemon, nil
+	return body, 0, nil
+}
+
+func setConditionalHeaders(req *http.Request, validators entities.Validators) {
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+}
+
+func validatorsOf(resp *http.Response) entities.Validators {
+	return entities.Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// Traduce el estado HTTP de PokeAPI a un error del dominio
+func checkStatus(resp *http.Response, notFoundMessage string) error {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return apperrors.New(apperrors.ErrNotFound, notFoundMessage)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return apperrors.New(apperrors.ErrUpstreamRateLimited, "")
+	case resp.StatusCode == http.StatusGatewayTimeout:
+		return apperrors.New(apperrors.ErrTimeout, "")
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return apperrors.Wrap(apperrors.ErrUpstreamUnavailable, "", fmt.Errorf("API return status %d", resp.StatusCode))
+	default:
+		return fmt.Errorf("API return status %d", resp.StatusCode)
+	}
+}
+
+// Clasifica los errores de red y de contexto en errores del dominio
+func classifyTransportError(message string, err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, resilience.ErrOpen):
+		return apperrors.Wrap(apperrors.ErrUpstreamUnavailable, "PokeAPI no esta disponible en este momento, intente mas tarde", err)
+	case errors.Is(err, resilience.ErrRateLimited):
+		return apperrors.Wrap(apperrors.ErrUpstreamRateLimited, "Se alcanzo el limite de consultas a PokeAPI, intente mas tarde", fmt.Errorf("%s: %w", message, err))
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperrors.Wrap(apperrors.ErrTimeout, "", fmt.Errorf("%s: %w", message, err))
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return apperrors.Wrap(apperrors.ErrTimeout, "", fmt.Errorf("%s: %w", message, err))
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("%s: %w", message, err)
+	default:
+		return apperrors.Wrap(apperrors.ErrUpstreamUnavailable, "", fmt.Errorf("%s: %w", message, err))
+	}
 }
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/infrastructure/resilience"
+)
+
+// RetryPolicy controla los reintentos de las solicitudes GET a PokeAPI
+type RetryPolicy struct {
+	// Intentos totales, incluido el primero; 1 desactiva los reintentos
+	MaxAttempts int
+	// Espera antes del primer reintento; se duplica en cada intento
+	BaseDelay time.Duration
+	// Tope de la espera exponencial
+	MaxDelay time.Duration
+	// Tiempo maximo de cada intento, dentro del plazo del contexto
+	AttemptTimeout time.Duration
+	// Mayor Retry-After que se respeta; si PokeAPI pide esperar mas, no se reintenta
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy devuelve la politica por defecto
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		BaseDelay:      200 * time.Millisecond,
+		MaxDelay:       5 * time.Second,
+		AttemptTimeout: 10 * time.Second,
+		MaxRetryAfter:  10 * time.Second,
+	}
+}
+
+// Ejecuta attempt hasta que tenga exito, falle con un error no reintentable,
+// se agoten los intentos o la espera no quepa en el plazo del contexto.
+// attempt devuelve el Retry-After indicado por PokeAPI, si lo hubo. Antes de
+// cada intento se llama a wait con el contexto de la solicitud, de modo que
+// la espera de turno no consume el tiempo del intento
+func (p RetryPolicy) do(ctx context.Context, wait func(ctx context.Context) error, attempt func(ctx context.Context) (time.Duration, error)) error {
+	backoff := p.BaseDelay
+
+	for n := 1; ; n++ {
+		if err := wait(ctx); err != nil {
+			return classifyTransportError("no se pudo esperar turno para consultar PokeAPI", err)
+		}
+
+		attemptCtx, cancel := p.attemptContext(ctx)
+		retryAfter, err := attempt(attemptCtx)
+		cancel()
+
+		if err == nil || n >= p.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+
+		delay := withJitter(min(backoff, p.MaxDelay))
+		if retryAfter > 0 {
+			if retryAfter > p.MaxRetryAfter {
+				return err
+			}
+			delay = retryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (p RetryPolicy) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.AttemptTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, p.AttemptTimeout)
+}
+
+// Solo se reintentan las fallas transitorias de PokeAPI; con el circuito
+// abierto no tiene sentido insistir
+func isRetryable(err error) bool {
+	if errors.Is(err, resilience.ErrOpen) {
+		return false
+	}
+	return errors.Is(err, apperrors.ErrUpstreamUnavailable) ||
+		errors.Is(err, apperrors.ErrTimeout) ||
+		errors.Is(err, apperrors.ErrUpstreamRateLimited)
+}
+
+// Espera entre la mitad y el total de d para que los reintentos de varias
+// solicitudes no coincidan
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// Lee Retry-After en segundos o como fecha HTTP en respuestas 429 y 503
+func retryAfter(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
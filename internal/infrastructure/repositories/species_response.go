@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// Idiomas preferidos para textos localizados, en orden
+var preferredLanguages = []string{"es", "en"}
+
+// Respuesta de /pokemon-species/{id} en PokeAPI
+type speciesResponse struct {
+	ID            int                      `json:"id"`
+	Name          string                   `json:"name"`
+	CaptureRate   int                      `json:"capture_rate"`
+	BaseHappiness *int                     `json:"base_happiness"`
+	IsLegendary   bool                     `json:"is_legendary"`
+	IsMythical    bool                     `json:"is_mythical"`
+	GrowthRate    *entities.NamedResource  `json:"growth_rate"`
+	Habitat       *entities.NamedResource  `json:"habitat"`
+	EggGroups     []entities.NamedResource `json:"egg_groups"`
+	Genera        []struct {
+		Genus    string                 `json:"genus"`
+		Language entities.NamedResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string                 `json:"flavor_text"`
+		Language   entities.NamedResource `json:"language"`
+	} `json:"flavor_text_entries"`
+	EvolutionChain *struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+}
+
+func (s *speciesResponse) toEntity() *entities.Species {
+	species := &entities.Species{
+		ID:            s.ID,
+		Name:          s.Name,
+		CaptureRate:   s.CaptureRate,
+		BaseHappiness: s.BaseHappiness,
+		IsLegendary:   s.IsLegendary,
+		IsMythical:    s.IsMythical,
+		EggGroups:     make([]string, 0, len(s.EggGroups)),
+	}
+
+	if s.GrowthRate != nil {
+		species.GrowthRate = s.GrowthRate.Name
+	}
+	if s.Habitat != nil {
+		species.Habitat = s.Habitat.Name
+	}
+	if s.EvolutionChain != nil {
+		species.EvolutionChainID = entities.IDFromURL(s.EvolutionChain.URL)
+	}
+	for _, group := range s.EggGroups {
+		species.EggGroups = append(species.EggGroups, group.Name)
+	}
+
+	// El genero y la descripcion se toman en el primer idioma disponible
+	for _, lang := range preferredLanguages {
+		for _, entry := range s.FlavorTextEntries {
+			if entry.Language.Name == lang {
+				species.FlavorText = cleanFlavorText(entry.FlavorText)
+				species.Language = lang
+				break
+			}
+		}
+		if species.FlavorText != "" {
+			break
+		}
+	}
+	genusLanguages := preferredLanguages
+	if species.Language != "" {
+		genusLanguages = append([]string{species.Language}, preferredLanguages...)
+	}
+	for _, lang := range genusLanguages {
+		for _, genus := range s.Genera {
+			if genus.Language.Name == lang {
+				species.Genus = genus.Genus
+				break
+			}
+		}
+		if species.Genus != "" {
+			break
+		}
+	}
+
+	return species
+}
+
+// PokeAPI incluye saltos de linea y de pagina de los juegos originales
+func cleanFlavorText(text string) string {
+	replacer := strings.NewReplacer("\n", " ", "\f", " ", "\u00ad", "")
+	return strings.Join(strings.Fields(replacer.Replace(text)), " ")
+}
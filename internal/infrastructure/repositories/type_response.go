@@ -0,0 +1,41 @@
+package repositories
+
+import "github.com/gerardstrujills/backend/internal/domain/entities"
+
+// Respuesta de /type/{name} en PokeAPI
+type typeResponse struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo   []entities.NamedResource `json:"double_damage_to"`
+		HalfDamageTo     []entities.NamedResource `json:"half_damage_to"`
+		NoDamageTo       []entities.NamedResource `json:"no_damage_to"`
+		DoubleDamageFrom []entities.NamedResource `json:"double_damage_from"`
+		HalfDamageFrom   []entities.NamedResource `json:"half_damage_from"`
+		NoDamageFrom     []entities.NamedResource `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+func (t *typeResponse) toEntity() *entities.PokemonType {
+	relations := t.DamageRelations
+	return &entities.PokemonType{
+		ID:   t.ID,
+		Name: t.Name,
+		DamageRelations: entities.DamageRelations{
+			DoubleDamageTo:   resourceNames(relations.DoubleDamageTo),
+			HalfDamageTo:     resourceNames(relations.HalfDamageTo),
+			NoDamageTo:       resourceNames(relations.NoDamageTo),
+			DoubleDamageFrom: resourceNames(relations.DoubleDamageFrom),
+			HalfDamageFrom:   resourceNames(relations.HalfDamageFrom),
+			NoDamageFrom:     resourceNames(relations.NoDamageFrom),
+		},
+	}
+}
+
+func resourceNames(resources []entities.NamedResource) []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.Name)
+	}
+	return names
+}
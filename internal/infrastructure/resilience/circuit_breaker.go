@@ -0,0 +1,225 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrOpen se devuelve sin llamar a PokeAPI mientras el circuito esta abierto
+var ErrOpen = errors.New("circuito abierto: PokeAPI no disponible temporalmente")
+
+// Estado del circuito
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerSettings configura cuando se abre y se vuelve a cerrar el circuito
+type BreakerSettings struct {
+	// Ventana en la que se cuentan solicitudes y fallas estando cerrado
+	Window time.Duration
+	// Solicitudes minimas en la ventana antes de evaluar la proporcion de fallas
+	MinRequests int
+	// Proporcion de fallas (0-1) que abre el circuito
+	FailureRatio float64
+	// Tiempo que el circuito permanece abierto antes de probar de nuevo
+	OpenTimeout time.Duration
+	// Solicitudes de prueba en semiabierto; si todas tienen exito se cierra
+	HalfOpenRequests int
+}
+
+// CircuitBreaker corta las solicitudes a un servicio que esta fallando para
+// no esperar sus tiempos de espera, y lo vuelve a probar pasado OpenTimeout
+type CircuitBreaker struct {
+	mutex    sync.Mutex
+	settings BreakerSettings
+	state    State
+
+	// generation cambia con cada transicion para descartar resultados de
+	// solicitudes iniciadas en un estado anterior
+	generation  uint64
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	successes   int
+
+	opened   int64
+	rejected int64
+}
+
+func NewCircuitBreaker(settings BreakerSettings) *CircuitBreaker {
+	settings.MinRequests = max(settings.MinRequests, 1)
+	settings.HalfOpenRequests = max(settings.HalfOpenRequests, 1)
+
+	return &CircuitBreaker{
+		settings:    settings,
+		windowStart: time.Now(),
+	}
+}
+
+// Allow indica si puede hacerse una solicitud. Si puede, done debe llamarse
+// con el resultado; record en false descarta la solicitud (por ejemplo si el
+// cliente la cancelo)
+func (b *CircuitBreaker) Allow() (done func(success, record bool), err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) < b.settings.OpenTimeout {
+			b.rejected++
+			return nil, ErrOpen
+		}
+		b.transition(StateHalfOpen, now)
+	case StateClosed:
+		if b.settings.Window > 0 && now.Sub(b.windowStart) >= b.settings.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+	}
+
+	if b.state == StateHalfOpen {
+		if b.probes >= b.settings.HalfOpenRequests {
+			b.rejected++
+			return nil, ErrOpen
+		}
+		b.probes++
+	}
+
+	generation := b.generation
+	return func(success, record bool) {
+		b.done(generation, success, record)
+	}, nil
+}
+
+func (b *CircuitBreaker) done(generation uint64, success, record bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	now := time.Now()
+	switch b.state {
+	case StateClosed:
+		if !record {
+			return
+		}
+		b.requests++
+		if !success {
+			b.failures++
+		}
+		if b.requests >= b.settings.MinRequests && float64(b.failures)/float64(b.requests) >= b.settings.FailureRatio {
+			b.transition(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if !record {
+			// Libera el lugar de prueba para otra solicitud
+			b.probes--
+			return
+		}
+		if !success {
+			b.transition(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.settings.HalfOpenRequests {
+			b.transition(StateClosed, now)
+		}
+	}
+}
+
+func (b *CircuitBreaker) transition(state State, now time.Time) {
+	b.state = state
+	b.generation++
+	b.windowStart, b.requests, b.failures = now, 0, 0
+	b.probes, b.successes = 0, 0
+	if state == StateOpen {
+		b.openedAt = now
+		b.opened++
+	}
+}
+
+// State devuelve el estado actual
+func (b *CircuitBreaker) State() State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.settings.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Health informa si PokeAPI se considera disponible
+func (b *CircuitBreaker) Health() (bool, map[string]interface{}) {
+	state := b.State()
+	return state != StateOpen, map[string]interface{}{
+		"circuit": state.String(),
+	}
+}
+
+// Metrics expone el estado y los contadores del circuito
+func (b *CircuitBreaker) Metrics() map[string]interface{} {
+	state := b.State()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return map[string]interface{}{
+		"state":           state.String(),
+		"times_opened":    b.opened,
+		"rejected":        b.rejected,
+		"window_requests": b.requests,
+		"window_failures": b.failures,
+	}
+}
+
+// Transport envuelve next para que cada solicitud pase por el circuito. Se
+// consideran fallas los errores de red, los tiempos agotados y los 5xx
+func (b *CircuitBreaker) Transport(next http.RoundTripper) http.RoundTripper {
+	return &breakerTransport{breaker: b, next: next}
+}
+
+type breakerTransport struct {
+	breaker *CircuitBreaker
+	next    http.RoundTripper
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := t.breaker.Allow()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		// Una cancelacion del cliente no dice nada sobre PokeAPI
+		canceled := errors.Is(req.Context().Err(), context.Canceled)
+		done(false, !canceled)
+	default:
+		done(resp.StatusCode < http.StatusInternalServerError, true)
+	}
+	return resp, err
+}
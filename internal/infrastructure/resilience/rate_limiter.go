@@ -0,0 +1,117 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited se devuelve cuando la espera por un turno no cabe en el
+// plazo de la solicitud o supera la espera maxima
+var ErrRateLimited = errors.New("limite de solicitudes salientes alcanzado")
+
+// RateLimiter es un token bucket: se reponen rate turnos por segundo hasta
+// burst. Quien no encuentra turno reserva el siguiente y espera en cola
+type RateLimiter struct {
+	mutex   sync.Mutex
+	rate    float64
+	burst   float64
+	maxWait time.Duration
+	tokens  float64
+	last    time.Time
+
+	waits    int64
+	waited   time.Duration
+	rejected int64
+	queued   int64
+}
+
+// NewRateLimiter crea el limitador; con rate <= 0 devuelve nil, que no limita.
+// maxWait acota la espera de cada solicitud (0 = solo el plazo del contexto)
+func NewRateLimiter(rate float64, burst int, maxWait time.Duration) *RateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		maxWait: maxWait,
+		tokens:  float64(max(burst, 1)),
+		last:    time.Now(),
+	}
+}
+
+// Wait bloquea hasta que haya un turno libre. Si la espera no cabe en el
+// plazo de ctx o en maxWait devuelve ErrRateLimited sin esperar; si ctx se
+// cancela mientras espera, devuelve su error y libera el turno reservado
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	l.refill(now)
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mutex.Unlock()
+		return nil
+	}
+
+	delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	deadline, hasDeadline := ctx.Deadline()
+	if (l.maxWait > 0 && delay > l.maxWait) || (hasDeadline && now.Add(delay).After(deadline)) {
+		l.rejected++
+		l.mutex.Unlock()
+		return ErrRateLimited
+	}
+
+	// Reserva el turno para que los siguientes queden detras en la cola
+	l.tokens--
+	l.queued++
+	l.mutex.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.mutex.Lock()
+		l.tokens = min(l.tokens+1, l.burst)
+		l.queued--
+		l.waited += time.Since(now)
+		l.mutex.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	l.mutex.Lock()
+	l.queued--
+	l.waits++
+	l.waited += time.Since(now)
+	l.mutex.Unlock()
+	return nil
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	l.tokens = min(l.tokens+elapsed*l.rate, l.burst)
+}
+
+// Metrics expone cuanto se ha esperado por turnos
+func (l *RateLimiter) Metrics() map[string]interface{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return map[string]interface{}{
+		"rate":         l.rate,
+		"burst":        int(l.burst),
+		"waits":        l.waits,
+		"wait_time_ms": l.waited.Milliseconds(),
+		"queued":       l.queued,
+		"rejected":     l.rejected,
+	}
+}
@@ -0,0 +1,269 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/repositories"
+)
+
+// Limite suficiente para que PokeAPI devuelva el listado completo
+const fullListLimit = 100000
+
+// Puntajes por tipo de coincidencia; dentro de cada grupo se penaliza la distancia
+const (
+	scoreExact     = 1000
+	scorePrefix    = 800
+	scoreSubstring = 600
+	scoreFuzzy     = 400
+
+	minTrigramSimilarity = 0.3
+)
+
+type indexEntry struct {
+	name     string
+	trigrams map[string]struct{}
+}
+
+// NameIndex mantiene en memoria todos los nombres de Pokemon y resuelve
+// busquedas por prefijo, subcadena y aproximadas sin consultar PokeAPI
+type NameIndex struct {
+	repo            repositories.PokemonRepository
+	refreshInterval time.Duration
+
+	mutex   sync.RWMutex
+	entries []indexEntry
+	listing []entities.PokemonResult
+
+	// Serializa las reconstrucciones para no descargar el listado en paralelo
+	buildMutex sync.Mutex
+}
+
+func NewNameIndex(repo repositories.PokemonRepository, refreshInterval time.Duration) *NameIndex {
+	index := &NameIndex{
+		repo:            repo,
+		refreshInterval: refreshInterval,
+	}
+
+	// Iniciar refresco periódico del listado; sin intervalo el indice solo se
+	// reconstruye con Refresh
+	if refreshInterval > 0 {
+		go index.refreshPeriodically()
+	}
+
+	return index
+}
+
+// Refresh descarga el listado completo y reemplaza el indice
+func (i *NameIndex) Refresh(ctx context.Context) error {
+	i.buildMutex.Lock()
+	defer i.buildMutex.Unlock()
+
+	return i.build(ctx)
+}
+
+// Size devuelve la cantidad de nombres indexados
+func (i *NameIndex) Size() int {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	return len(i.entries)
+}
+
+func (i *NameIndex) Search(ctx context.Context, query string) ([]entities.SearchMatch, error) {
+	if err := i.ensureBuilt(ctx); err != nil {
+		return nil, err
+	}
+
+	query = normalize(query)
+	if query == "" {
+		return []entities.SearchMatch{}, nil
+	}
+	queryTrigrams := trigrams(query)
+
+	i.mutex.RLock()
+	matches := make([]entities.SearchMatch, 0)
+	for _, entry := range i.entries {
+		if score, ok := scoreEntry(query, queryTrigrams, entry); ok {
+			matches = append(matches, entities.SearchMatch{Name: entry.name, Score: score})
+		}
+	}
+	i.mutex.RUnlock()
+
+	sort.Slice(matches, func(a, b int) bool {
+		return matches[a].RanksBefore(matches[b])
+	})
+	return matches, nil
+}
+
+// Listing devuelve el listado completo ordenado por ID. El indice lo
+// reemplaza entero al refrescarse, por lo que se puede leer sin copiarlo
+func (i *NameIndex) Listing(ctx context.Context) ([]entities.PokemonResult, error) {
+	if err := i.ensureBuilt(ctx); err != nil {
+		return nil, err
+	}
+
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	return i.listing, nil
+}
+
+// Construye el indice la primera vez que se necesita
+func (i *NameIndex) ensureBuilt(ctx context.Context) error {
+	if i.Size() > 0 {
+		return nil
+	}
+
+	i.buildMutex.Lock()
+	defer i.buildMutex.Unlock()
+
+	// Otro llamador pudo construirlo mientras esperabamos
+	if i.Size() > 0 {
+		return nil
+	}
+	return i.build(ctx)
+}
+
+func (i *NameIndex) build(ctx context.Context) error {
+	list, err := i.repo.GetList(ctx, fullListLimit, 0)
+	if err != nil {
+		return fmt.Errorf("no se pudo construir el indice de nombres: %w", err)
+	}
+
+	entries := make([]indexEntry, 0, len(list.Results))
+	for _, result := range list.Results {
+		name := strings.ToLower(result.Name)
+		entries = append(entries, indexEntry{name: name, trigrams: trigrams(name)})
+	}
+
+	// Las paginas por cursor avanzan por ID
+	listing := slices.Clone(list.Results)
+	sort.SliceStable(listing, func(a, b int) bool {
+		return listing[a].ID() < listing[b].ID()
+	})
+
+	i.mutex.Lock()
+	i.entries = entries
+	i.listing = listing
+	i.mutex.Unlock()
+
+	return nil
+}
+
+// Refresca periódicamente el listado para incluir Pokemon nuevos
+func (i *NameIndex) refreshPeriodically() {
+	ticker := time.NewTicker(i.refreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		if err := i.Refresh(ctx); err != nil {
+			log.Printf("no se pudo refrescar el indice de nombres: %v", err)
+		}
+		cancel()
+	}
+}
+
+// Calcula la relevancia de un nombre para la consulta
+func scoreEntry(query string, queryTrigrams map[string]struct{}, entry indexEntry) (float64, bool) {
+	name := entry.name
+	switch {
+	case name == query:
+		return scoreExact, true
+	case strings.HasPrefix(name, query):
+		return scorePrefix - float64(len(name)-len(query)), true
+	case strings.Contains(name, query):
+		return scoreSubstring - float64(strings.Index(name, query)), true
+	}
+
+	// Coincidencia aproximada: errores de tipeo o nombres parecidos. La
+	// distancia es al menos la diferencia de largos, asi que si esta supera la
+	// tolerancia solo los trigramas pueden aceptar el nombre y Levenshtein se
+	// calcula unicamente para los que pasan ese filtro
+	typos := maxTypos(query)
+	similarity := trigramSimilarity(queryTrigrams, entry.trigrams)
+	lengthDiff := utf8.RuneCountInString(query) - utf8.RuneCountInString(name)
+	if max(lengthDiff, -lengthDiff) > typos && similarity < minTrigramSimilarity {
+		return 0, false
+	}
+
+	distance := levenshtein(query, name)
+	if distance <= typos || similarity >= minTrigramSimilarity {
+		return scoreFuzzy + 100*similarity - 10*float64(distance), true
+	}
+	return 0, false
+}
+
+// Tolerancia de errores segun el largo de la consulta en caracteres
+func maxTypos(query string) int {
+	length := utf8.RuneCountInString(query)
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func normalize(query string) string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	return strings.Join(strings.Fields(query), "-")
+}
+
+// Trigramas del texto con relleno para dar peso a inicio y fin
+func trigrams(text string) map[string]struct{} {
+	padded := "  " + text + " "
+	runes := []rune(padded)
+	set := make(map[string]struct{}, len(runes))
+	for idx := 0; idx+3 <= len(runes); idx++ {
+		set[string(runes[idx:idx+3])] = struct{}{}
+	}
+	return set
+}
+
+// Similitud de Jaccard entre dos conjuntos de trigramas
+func trigramSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
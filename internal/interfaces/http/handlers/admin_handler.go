@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gerardstrujills/backend/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	cacheAdminUseCase *usecases.CacheAdminUseCase
+}
+
+func NewAdminHandler(cacheAdminUseCase *usecases.CacheAdminUseCase) *AdminHandler {
+	return &AdminHandler{
+		cacheAdminUseCase: cacheAdminUseCase,
+	}
+}
+
+// GET /admin/cache/stats
+func (h *AdminHandler) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.cacheAdminUseCase.Stats(),
+	})
+}
+
+// DELETE /admin/cache/:key
+func (h *AdminHandler) DeleteCacheKey(c *gin.Context) {
+	key := c.Param("key")
+	if err := h.cacheAdminUseCase.DeleteKey(c.Request.Context(), key); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleted": key,
+	})
+}
+
+// DELETE /admin/cache?prefix=pokemon:search
+func (h *AdminHandler) DeleteCachePrefix(c *gin.Context) {
+	prefix := c.Query("prefix")
+	deleted, err := h.cacheAdminUseCase.DeletePrefix(c.Request.Context(), prefix)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"prefix":  prefix,
+		"deleted": deleted,
+	})
+}
+
+// POST /admin/cache/clear
+func (h *AdminHandler) ClearCache(c *gin.Context) {
+	if err := h.cacheAdminUseCase.Clear(c.Request.Context()); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cleared": true,
+	})
+}
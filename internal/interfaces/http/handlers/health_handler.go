@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gerardstrujills/backend/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	reporters map[string]services.HealthReporter
+}
+
+// NewHealthHandler recibe los componentes a informar indexados por nombre
+func NewHealthHandler(reporters map[string]services.HealthReporter) *HealthHandler {
+	return &HealthHandler{
+		reporters: reporters,
+	}
+}
+
+// GET /health responde 200 mientras el servicio este en pie; si alguna
+// dependencia falla el estado pasa a "degraded"
+func (h *HealthHandler) GetHealth(c *gin.Context) {
+	status := "ok"
+	checks := make(gin.H, len(h.reporters))
+	for name, reporter := range h.reporters {
+		healthy, details := reporter.Health()
+		if !healthy {
+			status = "degraded"
+		}
+
+		check := gin.H{"healthy": healthy}
+		for key, value := range details {
+			check[key] = value
+		}
+		checks[name] = check
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  status,
+		"service": "hortifrut-backend",
+		"checks":  checks,
+	})
+}
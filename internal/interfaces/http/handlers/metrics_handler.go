@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gerardstrujills/backend/internal/domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsHandler struct {
+	providers map[string]services.MetricsProvider
+}
+
+// NewMetricsHandler recibe las fuentes de metricas indexadas por seccion
+func NewMetricsHandler(providers map[string]services.MetricsProvider) *MetricsHandler {
+	return &MetricsHandler{
+		providers: providers,
+	}
+}
+
+// GET /metrics
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	metrics := make(gin.H, len(h.providers))
+	for section, provider := range h.providers {
+		metrics[section] = provider.Metrics()
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/interfaces/http/cursor"
+	"github.com/gin-gonic/gin"
+)
+
+// pager lee las paginas solicitadas y arma los cursores y enlaces de las
+// respuestas. Los enlaces parten de la URL publica configurada y nunca de
+// cabeceras de la solicitud, que el cliente puede falsificar
+type pager struct {
+	codec   *cursor.Codec
+	baseURL string
+}
+
+// Sin baseURL los enlaces son relativos a la raiz del servidor
+func newPager(codec *cursor.Codec, baseURL string) *pager {
+	return &pager{
+		codec:   codec,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Pagina solicitada, por limit/offset o por cursor. Con cursor la pagina
+// empieza despues de after o termina antes de before
+type pageRequest struct {
+	limit       int
+	offset      int
+	fingerprint string
+	withCursor  bool
+	after       *cursor.Position
+	before      *cursor.Position
+}
+
+// Pagina obtenida: su posicion en el resultado completo, cuantos elementos
+// trae y los elementos de los extremos, de los que salen los cursores
+type pageResult struct {
+	offset int
+	count  int
+	total  int
+	first  *cursor.Position
+	last   *cursor.Position
+}
+
+// Lee la pagina de la solicitud. Con ?cursor= la posicion sale del cursor
+// firmado y ?limit= puede cambiar el tamaño; sin el se mantiene el modo
+// limit/offset de siempre
+func (p *pager) parse(c *gin.Context, fingerprint string, defaultLimit, maxLimit int) (pageRequest, error) {
+	page := pageRequest{fingerprint: fingerprint}
+
+	if token := c.Query("cursor"); token != "" {
+		if _, hasOffset := c.GetQuery("offset"); hasOffset {
+			return page, apperrors.New(apperrors.ErrInvalidInput, "No se puede combinar 'cursor' con 'offset'")
+		}
+
+		decoded, err := p.codec.Decode(token, fingerprint)
+		if err != nil {
+			return page, apperrors.Wrap(apperrors.ErrInvalidInput, "Cursor no válido", err)
+		}
+		if decoded.Limit > maxLimit {
+			return page, apperrors.New(apperrors.ErrInvalidInput, "Cursor no válido")
+		}
+
+		page.limit = decoded.Limit
+		if value, hasLimit := c.GetQuery("limit"); hasLimit {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit <= 0 || limit > maxLimit {
+				return page, apperrors.New(apperrors.ErrInvalidInput, "El parámetro 'limit' debe estar entre 1 y "+strconv.Itoa(maxLimit))
+			}
+			page.limit = limit
+		}
+
+		page.after = decoded.After
+		page.before = decoded.Before
+		page.withCursor = true
+		return page, nil
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		limit = defaultLimit
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	page.limit = limit
+	page.offset = offset
+	return page, nil
+}
+
+// Posicion desde la que resolver una pagina por cursor; backward indica que
+// se piden los elementos anteriores a ella
+func (r pageRequest) position() (position cursor.Position, backward bool) {
+	if r.before != nil {
+		return *r.before, true
+	}
+	return *r.after, false
+}
+
+// Metadatos de paginacion con enlaces que apuntan a nuestra propia API
+func (p *pager) pagination(c *gin.Context, page pageRequest, result pageResult) gin.H {
+	next, previous := p.links(c, page, result)
+	nextCursor, previousCursor := p.cursors(page, result)
+	// has_more coincide con next: una pagina por cursor vacia no tiene desde
+	// donde seguir
+	return gin.H{
+		"limit":           page.limit,
+		"offset":          result.offset,
+		"total":           result.total,
+		"has_more":        next != nil,
+		"next":            next,
+		"previous":        previous,
+		"next_cursor":     nextCursor,
+		"previous_cursor": previousCursor,
+	}
+}
+
+// Construye las URL de la pagina siguiente y anterior a partir de la
+// solicitud actual, conservando el resto de parametros. Siguen el mismo modo
+// de paginacion que la solicitud
+func (p *pager) links(c *gin.Context, page pageRequest, result pageResult) (next, previous *string) {
+	if !page.withCursor {
+		if hasNext(result) {
+			link := p.offsetURL(c, page.limit, result.offset+page.limit)
+			next = &link
+		}
+		if result.offset > 0 {
+			link := p.offsetURL(c, page.limit, max(result.offset-page.limit, 0))
+			previous = &link
+		}
+		return next, previous
+	}
+
+	nextCursor, previousCursor := p.cursors(page, result)
+	if nextCursor != nil {
+		link := p.cursorURL(c, *nextCursor)
+		next = &link
+	}
+	if previousCursor != nil {
+		link := p.cursorURL(c, *previousCursor)
+		previous = &link
+	}
+	return next, previous
+}
+
+// La pagina siguiente empieza despues del ultimo elemento y la anterior
+// termina antes del primero
+func (p *pager) cursors(page pageRequest, result pageResult) (next, previous *string) {
+	if hasNext(result) && result.last != nil {
+		token := p.codec.Encode(cursor.Cursor{After: result.last, Limit: page.limit, Fingerprint: page.fingerprint})
+		next = &token
+	}
+	if result.offset > 0 && result.first != nil {
+		token := p.codec.Encode(cursor.Cursor{Before: result.first, Limit: page.limit, Fingerprint: page.fingerprint})
+		previous = &token
+	}
+	return next, previous
+}
+
+func hasNext(result pageResult) bool {
+	return result.offset+result.count < result.total
+}
+
+func (p *pager) offsetURL(c *gin.Context, limit, offset int) string {
+	query := c.Request.URL.Query()
+	query.Del("cursor")
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return p.pageURL(c, query)
+}
+
+// El cursor ya lleva el tamaño de pagina
+func (p *pager) cursorURL(c *gin.Context, token string) string {
+	query := c.Request.URL.Query()
+	query.Del("limit")
+	query.Set("cursor", token)
+	return p.pageURL(c, query)
+}
+
+func (p *pager) pageURL(c *gin.Context, query url.Values) string {
+	return p.baseURL + c.Request.URL.Path + "?" + query.Encode()
+}
@@ -3,18 +3,32 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/interfaces/http/cursor"
 	"github.com/gerardstrujills/backend/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
 
+// Largo maximo de la consulta de busqueda, en caracteres
+const maxSearchQueryLength = 50
+
 type PokemonHandler struct {
-	pokemonUseCase *usecases.PokemonUseCase
+	pokemonUseCase    *usecases.PokemonUseCase
+	comparisonUseCase *usecases.ComparisonUseCase
+	pager             *pager
 }
 
-func NewPokemonHandler(pokemonUseCase *usecases.PokemonUseCase) *PokemonHandler {
+// NewPokemonHandler crea el handler; publicBaseURL es la URL publica de la
+// API con la que se arman los enlaces de paginacion (vacia = enlaces relativos)
+func NewPokemonHandler(pokemonUseCase *usecases.PokemonUseCase, comparisonUseCase *usecases.ComparisonUseCase, cursorCodec *cursor.Codec, publicBaseURL string) *PokemonHandler {
 	return &PokemonHandler{
-		pokemonUseCase: pokemonUseCase,
+		pokemonUseCase:    pokemonUseCase,
+		comparisonUseCase: comparisonUseCase,
+		pager:             newPager(cursorCodec, publicBaseURL),
 	}
 }
 
@@ -23,26 +37,18 @@ func (h *PokemonHandler) GetPokemonByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "ID de Pokemon no válido",
-		})
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "ID de Pokemon no válido"))
 		return
 	}
 
 	pokemon, err := h.pokemonUseCase.GetPokemonByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "pokemon not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Pokemon no encontrado",
-			})
-			return
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "No se pudo obtener el Pokemon",
-		})
+		c.Error(err)
 		return
 	}
+	if !includeMoves(c) {
+		pokemon = pokemon.WithoutMoves()
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":   pokemon,
@@ -54,61 +60,158 @@ func (h *PokemonHandler) GetPokemonByID(c *gin.Context) {
 func (h *PokemonHandler) GetPokemonByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "El nombre del Pokemon es obligatorio",
-		})
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "El nombre del Pokemon es obligatorio"))
 		return
 	}
 
 	pokemon, err := h.pokemonUseCase.GetPokemonByName(c.Request.Context(), name)
 	if err != nil {
-		if err.Error() == "pokemon not found" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Pokemon no encontrado",
-			})
+		c.Error(err)
+		return
+	}
+	if !includeMoves(c) {
+		pokemon = pokemon.WithoutMoves()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": pokemon,
+	})
+}
+
+// GET /pokemon/:id/species
+func (h *PokemonHandler) GetSpeciesByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "ID de Pokemon no válido"))
+		return
+	}
+
+	species, err := h.pokemonUseCase.GetSpeciesByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": species,
+	})
+}
+
+// GET /pokemon/:id/evolutions
+func (h *PokemonHandler) GetEvolutionChain(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "ID de Pokemon no válido"))
+		return
+	}
+
+	chain, err := h.pokemonUseCase.GetEvolutionChain(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": chain,
+	})
+}
+
+// GET /pokemon/name/:name/species
+func (h *PokemonHandler) GetSpeciesByName(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "El nombre del Pokemon es obligatorio"))
+		return
+	}
+
+	species, err := h.pokemonUseCase.GetSpeciesByName(c.Request.Context(), name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": species,
+	})
+}
+
+// GET /pokemon/compare?ids=25,26,133
+func (h *PokemonHandler) ComparePokemon(c *gin.Context) {
+	idsParam := c.Query("ids")
+	if idsParam == "" {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "El parámetro 'ids' es obligatorio"))
+		return
+	}
+
+	var refs []string
+	seen := make(map[int]bool)
+	for _, idStr := range strings.Split(idsParam, ",") {
+		idStr = strings.TrimSpace(idStr)
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			c.Error(apperrors.New(apperrors.ErrInvalidInput, "ID de Pokemon no válido: "+idStr))
+			return
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		refs = append(refs, strconv.Itoa(id))
+		// No seguir leyendo una lista que ya supera el limite
+		if len(refs) > usecases.MaxCompared {
+			c.Error(usecases.ComparedCountError())
 			return
 		}
+	}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "No se pudo obtener el Pokemon",
-		})
+	comparison, err := h.comparisonUseCase.ComparePokemon(c.Request.Context(), refs)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": pokemon,
+		"data":    comparison,
+		"partial": len(comparison.Errors) > 0,
 	})
 }
 
 // GET /pokemon
 func (h *PokemonHandler) GetPokemonList(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 100 {
-		limit = 20
+	page, err := h.pager.parse(c, cursor.Fingerprint("list"), 20, 100)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	var pokemonList *entities.PokemonList
+	offset := page.offset
+	if page.withCursor {
+		position, backward := page.position()
+		pokemonList, offset, err = h.pokemonUseCase.GetPokemonListFrom(c.Request.Context(), position.ID, backward, page.limit)
+	} else {
+		pokemonList, err = h.pokemonUseCase.GetPokemonList(c.Request.Context(), page.limit, page.offset)
 	}
-
-	pokemonList, err := h.pokemonUseCase.GetPokemonList(c.Request.Context(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "No se pudo obtener la lista de Pokemon",
-		})
+		c.Error(err)
 		return
 	}
 
+	result := pageResult{offset: offset, count: len(pokemonList.Results), total: pokemonList.Count}
+	if count := len(pokemonList.Results); count > 0 {
+		result.first = &cursor.Position{ID: pokemonList.Results[0].ID()}
+		result.last = &cursor.Position{ID: pokemonList.Results[count-1].ID()}
+	}
+
+	// Reemplazar los enlaces de PokeAPI sin modificar la lista cacheada
+	list := *pokemonList
+	list.Next, list.Previous = h.pager.links(c, page, result)
+
 	c.JSON(http.StatusOK, gin.H{
-		"data": pokemonList,
-		"pagination": gin.H{
-			"limit":  limit,
-			"offset": offset,
-		},
+		"data":       list,
+		"pagination": h.pager.pagination(c, page, result),
 	})
 }
 
@@ -116,40 +219,62 @@ func (h *PokemonHandler) GetPokemonList(c *gin.Context) {
 func (h *PokemonHandler) SearchPokemonByTitle(c *gin.Context) {
 	title := c.Query("q")
 	if title == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Search query 'q' is required",
-		})
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "Search query 'q' is required"))
 		return
 	}
-
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 50 {
-		limit = 10
+	// Limita el costo de la busqueda aproximada, que recorre todo el indice
+	if utf8.RuneCountInString(title) > maxSearchQueryLength {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "La consulta 'q' no puede superar "+strconv.Itoa(maxSearchQueryLength)+" caracteres"))
+		return
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	page, err := h.pager.parse(c, cursor.Fingerprint("search", strings.ToLower(title)), 10, 50)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	pokemonList, err := h.pokemonUseCase.SearchPokemonByTitle(c.Request.Context(), title, limit, offset)
+	var searchResult *entities.SearchResult
+	if page.withCursor {
+		position, backward := page.position()
+		match := entities.SearchMatch{Name: position.Name, Score: position.Score}
+		searchResult, err = h.pokemonUseCase.SearchPokemonFrom(c.Request.Context(), title, match, backward, page.limit)
+	} else {
+		searchResult, err = h.pokemonUseCase.SearchPokemonByTitle(c.Request.Context(), title, page.limit, page.offset)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "No se pudo buscar Pokemon",
-		})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": pokemonList,
-		"search": gin.H{
-			"query":  title,
-			"limit":  limit,
-			"offset": offset,
-			"count":  len(pokemonList),
-		},
-	})
+	result := pageResult{
+		offset: searchResult.Offset,
+		count:  len(searchResult.Results) + len(searchResult.Errors),
+		total:  searchResult.Total,
+	}
+	if searchResult.First != nil && searchResult.Last != nil {
+		result.first = &cursor.Position{Score: searchResult.First.Score, Name: searchResult.First.Name}
+		result.last = &cursor.Position{Score: searchResult.Last.Score, Name: searchResult.Last.Name}
+	}
+
+	search := h.pager.pagination(c, page, result)
+	search["query"] = title
+	search["count"] = len(searchResult.Results)
+
+	response := gin.H{
+		"data":   searchResult.Results,
+		"search": search,
+	}
+	// Candidatos de la pagina que no se pudieron obtener
+	if len(searchResult.Errors) > 0 {
+		response["errors"] = searchResult.Errors
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// La lista de movimientos es pesada; se omite con ?include_moves=false
+func includeMoves(c *gin.Context) bool {
+	include, err := strconv.ParseBool(c.DefaultQuery("include_moves", "true"))
+	return err != nil || include
 }
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type TeamHandler struct {
+	teamUseCase *usecases.TeamUseCase
+}
+
+func NewTeamHandler(teamUseCase *usecases.TeamUseCase) *TeamHandler {
+	return &TeamHandler{
+		teamUseCase: teamUseCase,
+	}
+}
+
+// Cuerpo de POST /teams/analyze
+type analyzeTeamRequest struct {
+	Pokemon []pokemonRef `json:"pokemon"`
+}
+
+// Referencia a un Pokemon que acepta ID numerico o nombre en el JSON
+type pokemonRef string
+
+func (r *pokemonRef) UnmarshalJSON(data []byte) error {
+	var id int
+	if err := json.Unmarshal(data, &id); err == nil {
+		*r = pokemonRef(strconv.Itoa(id))
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("referencia de Pokemon no valida: %s", data)
+	}
+	*r = pokemonRef(name)
+	return nil
+}
+
+// POST /teams/analyze
+func (h *TeamHandler) AnalyzeTeam(c *gin.Context) {
+	var request analyzeTeamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(apperrors.Wrap(apperrors.ErrInvalidInput, "Cuerpo de la solicitud no válido", err))
+		return
+	}
+
+	refs := make([]string, 0, len(request.Pokemon))
+	for _, ref := range request.Pokemon {
+		if ref == "" {
+			c.Error(apperrors.New(apperrors.ErrInvalidInput, "Las referencias de Pokemon no pueden estar vacías"))
+			return
+		}
+		refs = append(refs, string(ref))
+	}
+
+	analysis, err := h.teamUseCase.AnalyzeTeam(c.Request.Context(), refs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": analysis,
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type TypeHandler struct {
+	typeUseCase *usecases.TypeUseCase
+}
+
+func NewTypeHandler(typeUseCase *usecases.TypeUseCase) *TypeHandler {
+	return &TypeHandler{
+		typeUseCase: typeUseCase,
+	}
+}
+
+// GET /types
+func (h *TypeHandler) ListTypes(c *gin.Context) {
+	types, err := h.typeUseCase.ListTypes(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  types,
+		"count": len(types),
+	})
+}
+
+// GET /types/:name
+func (h *TypeHandler) GetType(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "El nombre del tipo es obligatorio"))
+		return
+	}
+
+	detail, err := h.typeUseCase.GetType(c.Request.Context(), name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": detail,
+	})
+}
+
+// GET /pokemon/:id/weaknesses
+func (h *TypeHandler) GetPokemonWeaknesses(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.Error(apperrors.New(apperrors.ErrInvalidInput, "ID de Pokemon no válido"))
+		return
+	}
+
+	weaknesses, err := h.typeUseCase.GetPokemonWeaknesses(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": weaknesses,
+	})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gerardstrujills/backend/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type WarmupHandler struct {
+	warmupUseCase *usecases.WarmupUseCase
+}
+
+func NewWarmupHandler(warmupUseCase *usecases.WarmupUseCase) *WarmupHandler {
+	return &WarmupHandler{
+		warmupUseCase: warmupUseCase,
+	}
+}
+
+// GET /warmup
+func (h *WarmupHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"data": h.warmupUseCase.Status(),
+	})
+}
+
+// GET /ready responde 503 hasta que termina el precalentamiento de la cache
+func (h *WarmupHandler) Ready(c *gin.Context) {
+	status := h.warmupUseCase.Status()
+	code := http.StatusOK
+	if !status.Ready {
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, gin.H{
+		"ready":  status.Ready,
+		"warmup": status,
+	})
+}
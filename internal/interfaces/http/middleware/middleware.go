@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"log"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/usecases"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,7 +18,7 @@ func CORS() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Admin-Token, accept, origin, Cache-Control, X-Requested-With")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -42,18 +47,112 @@ func Logger() gin.HandlerFunc {
 	})
 }
 
-// Manejo centralizado de errores
+// Protege las rutas de administracion con un token enviado en
+// "Authorization: Bearer <token>" o en X-Admin-Token. Sin token configurado
+// las rutas quedan deshabilitadas
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Error(apperrors.New(apperrors.ErrUnauthorized, "Administracion deshabilitada: ADMIN_TOKEN no esta definido"))
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Token")
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			provided = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.Error(apperrors.New(apperrors.ErrUnauthorized, "Token de administrador no valido"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Marca con X-Cache-Stale las respuestas servidas con datos vencidos de la cache
+func CacheFreshness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, freshness := usecases.WithFreshness(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &freshnessWriter{ResponseWriter: c.Writer, freshness: freshness}
+
+		c.Next()
+	}
+}
+
+// La cabecera debe fijarse justo antes de enviar el estado, cuando el handler
+// ya consulto la cache
+type freshnessWriter struct {
+	gin.ResponseWriter
+	freshness *usecases.Freshness
+}
+
+func (w *freshnessWriter) WriteHeaderNow() {
+	w.markStale()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *freshnessWriter) Write(data []byte) (int, error) {
+	w.markStale()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *freshnessWriter) WriteString(s string) (int, error) {
+	w.markStale()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *freshnessWriter) markStale() {
+	if !w.Written() && w.Status() < http.StatusBadRequest && w.freshness.Stale() {
+		w.Header().Set("X-Cache-Stale", "true")
+	}
+}
+
+// Manejo centralizado de errores: traduce los errores del dominio a
+// codigos HTTP y a un cuerpo JSON con codigo legible por maquina
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last()
-			log.Printf("Error: %v", err.Error())
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
 
-			c.JSON(500, gin.H{
-				"error": "Internal server error",
-			})
+		err := c.Errors.Last().Err
+		code := apperrors.CodeOf(err)
+		status := StatusForCode(code)
+		if status >= http.StatusInternalServerError {
+			log.Printf("Error: %v", err)
 		}
+
+		c.JSON(status, gin.H{
+			"error": gin.H{
+				"code":    code,
+				"message": apperrors.MessageOf(err),
+			},
+		})
+	}
+}
+
+// StatusForCode devuelve el estado HTTP asociado a un codigo de error
+func StatusForCode(code string) int {
+	switch code {
+	case apperrors.CodeNotFound:
+		return http.StatusNotFound
+	case apperrors.CodeInvalidInput:
+		return http.StatusBadRequest
+	case apperrors.CodeUnauthorized:
+		return http.StatusUnauthorized
+	case apperrors.CodeUpstreamRateLimited:
+		return http.StatusTooManyRequests
+	case apperrors.CodeTimeout:
+		return http.StatusGatewayTimeout
+	case apperrors.CodeUpstreamUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
 	}
 }
@@ -6,19 +6,31 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(r *gin.Engine, pokemonHandler *handlers.PokemonHandler) {
+func SetupRoutes(r *gin.Engine, pokemonHandler *handlers.PokemonHandler, typeHandler *handlers.TypeHandler, teamHandler *handlers.TeamHandler, metricsHandler *handlers.MetricsHandler, adminHandler *handlers.AdminHandler, adminToken string, warmupHandler *handlers.WarmupHandler, healthHandler *handlers.HealthHandler) {
 	// Middleware global
 	r.Use(middleware.CORS())
 	r.Use(middleware.Logger())
 	r.Use(middleware.ErrorHandler())
+	r.Use(middleware.CacheFreshness())
 
 	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"service": "hortifrut-backend",
-		})
-	})
+	r.GET("/health", healthHandler.GetHealth)
+
+	// Preparacion: 503 hasta que termina el precalentamiento de la cache
+	r.GET("/ready", warmupHandler.Ready)
+	r.GET("/warmup", warmupHandler.GetStatus)
+
+	// Metricas internas
+	r.GET("/metrics", metricsHandler.GetMetrics)
+
+	// Administracion de la cache
+	admin := r.Group("/admin", middleware.AdminAuth(adminToken))
+	{
+		admin.GET("/cache/stats", adminHandler.GetCacheStats)    // GET /admin/cache/stats
+		admin.DELETE("/cache/:key", adminHandler.DeleteCacheKey) // DELETE /admin/cache/pokemon:id:25
+		admin.DELETE("/cache", adminHandler.DeleteCachePrefix)   // DELETE /admin/cache?prefix=pokemon:search
+		admin.POST("/cache/clear", adminHandler.ClearCache)      // POST /admin/cache/clear
+	}
 
 	// API v1 routes
 	v1 := r.Group("/api/v1")
@@ -26,10 +38,28 @@ func SetupRoutes(r *gin.Engine, pokemonHandler *handlers.PokemonHandler) {
 		// Pokemon routes
 		pokemon := v1.Group("/pokemon")
 		{
-			pokemon.GET("", pokemonHandler.GetPokemonList)              // GET /api/v1/pokemon?limit=20&offset=0
-			pokemon.GET("/search", pokemonHandler.SearchPokemonByTitle) // GET /api/v1/pokemon/search?q=pika&limit=10&offset=0
-			pokemon.GET("/:id", pokemonHandler.GetPokemonByID)          // GET /api/v1/pokemon/25
-			pokemon.GET("/name/:name", pokemonHandler.GetPokemonByName) // GET /api/v1/pokemon/name/pikachu
+			pokemon.GET("", pokemonHandler.GetPokemonList)                      // GET /api/v1/pokemon?limit=20&offset=0
+			pokemon.GET("/search", pokemonHandler.SearchPokemonByTitle)         // GET /api/v1/pokemon/search?q=pika&limit=10&offset=0
+			pokemon.GET("/compare", pokemonHandler.ComparePokemon)              // GET /api/v1/pokemon/compare?ids=25,26,133
+			pokemon.GET("/:id", pokemonHandler.GetPokemonByID)                  // GET /api/v1/pokemon/25
+			pokemon.GET("/:id/species", pokemonHandler.GetSpeciesByID)          // GET /api/v1/pokemon/25/species
+			pokemon.GET("/:id/evolutions", pokemonHandler.GetEvolutionChain)    // GET /api/v1/pokemon/25/evolutions
+			pokemon.GET("/:id/weaknesses", typeHandler.GetPokemonWeaknesses)    // GET /api/v1/pokemon/25/weaknesses
+			pokemon.GET("/name/:name", pokemonHandler.GetPokemonByName)         // GET /api/v1/pokemon/name/pikachu
+			pokemon.GET("/name/:name/species", pokemonHandler.GetSpeciesByName) // GET /api/v1/pokemon/name/pikachu/species
+		}
+
+		// Type routes
+		types := v1.Group("/types")
+		{
+			types.GET("", typeHandler.ListTypes)     // GET /api/v1/types
+			types.GET("/:name", typeHandler.GetType) // GET /api/v1/types/fire
+		}
+
+		// Team routes
+		teams := v1.Group("/teams")
+		{
+			teams.POST("/analyze", teamHandler.AnalyzeTeam) // POST /api/v1/teams/analyze
 		}
 	}
 }
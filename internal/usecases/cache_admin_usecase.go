@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+// CacheAdminUseCase permite inspeccionar e invalidar la cache
+type CacheAdminUseCase struct {
+	cacheService services.CacheService
+	stats        services.CacheStatsProvider
+}
+
+func NewCacheAdminUseCase(cacheService services.CacheService, stats services.CacheStatsProvider) *CacheAdminUseCase {
+	return &CacheAdminUseCase{
+		cacheService: cacheService,
+		stats:        stats,
+	}
+}
+
+// Stats devuelve los contadores por espacio de nombres
+func (uc *CacheAdminUseCase) Stats() map[string]entities.CacheStats {
+	return uc.stats.CacheStats()
+}
+
+// DeleteKey elimina una clave de la cache
+func (uc *CacheAdminUseCase) DeleteKey(ctx context.Context, key string) error {
+	if key == "" {
+		return apperrors.New(apperrors.ErrInvalidInput, "La clave no puede estar vacia")
+	}
+	if err := uc.cacheService.Delete(ctx, key); err != nil {
+		return fmt.Errorf("no se pudo eliminar la clave de cache: %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeletePrefix elimina todas las claves que empiezan con prefix y devuelve
+// cuantas se eliminaron
+func (uc *CacheAdminUseCase) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	if prefix == "" {
+		return 0, apperrors.New(apperrors.ErrInvalidInput, "El parametro 'prefix' es requerido; para vaciar la cache use /admin/cache/clear")
+	}
+
+	keys, err := uc.cacheService.Keys(ctx, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("no se pudieron listar las claves de cache: %s: %w", prefix, err)
+	}
+
+	for i, key := range keys {
+		if err := uc.cacheService.Delete(ctx, key); err != nil {
+			return i, fmt.Errorf("no se pudo eliminar la clave de cache: %s: %w", key, err)
+		}
+	}
+	return len(keys), nil
+}
+
+// Clear vacia la cache
+func (uc *CacheAdminUseCase) Clear(ctx context.Context) error {
+	if err := uc.cacheService.Clear(ctx); err != nil {
+		return fmt.Errorf("no se pudo vaciar la cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// Cantidad de Pokemon que se pueden comparar a la vez
+const (
+	MinCompared = 2
+	MaxCompared = 6
+)
+
+const (
+	comparisonWorkers = 3
+	statTotal         = "total"
+)
+
+type ComparisonUseCase struct {
+	pokemonUseCase *PokemonUseCase
+	typeUseCase    *TypeUseCase
+}
+
+func NewComparisonUseCase(pokemonUseCase *PokemonUseCase, typeUseCase *TypeUseCase) *ComparisonUseCase {
+	return &ComparisonUseCase{
+		pokemonUseCase: pokemonUseCase,
+		typeUseCase:    typeUseCase,
+	}
+}
+
+// ComparedCountError es el error para una cantidad de Pokemon fuera de
+// MinCompared y MaxCompared
+func ComparedCountError() error {
+	return apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("Se deben comparar entre %d y %d Pokemon", MinCompared, MaxCompared))
+}
+
+// ComparePokemon compara varios Pokemon. Las referencias que fallan se
+// informan en Errors sin invalidar el resto; solo falla si ninguna se obtuvo
+func (uc *ComparisonUseCase) ComparePokemon(ctx context.Context, refs []string) (*entities.PokemonComparison, error) {
+	if len(refs) < MinCompared || len(refs) > MaxCompared {
+		return nil, ComparedCountError()
+	}
+
+	chart, err := uc.typeUseCase.GetTypeChart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results, errs := uc.pokemonUseCase.GetPokemonBatch(ctx, refs, comparisonWorkers)
+
+	comparison := &entities.PokemonComparison{
+		Pokemon:  []entities.ComparedPokemon{},
+		Stats:    []entities.StatComparison{},
+		Matchups: []entities.TypeMatchup{},
+		Sizes:    []entities.SizeComparison{},
+		Errors:   []entities.ComparisonError{},
+	}
+
+	var found []*entities.Pokemon
+	var firstErr error
+	for i, pokemon := range results {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			comparison.Errors = append(comparison.Errors, entities.ComparisonError{
+				Ref:     refs[i],
+				Code:    apperrors.CodeOf(errs[i]),
+				Message: apperrors.MessageOf(errs[i]),
+			})
+			continue
+		}
+		found = append(found, pokemon)
+	}
+
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no se pudo obtener ningun Pokemon para comparar: %w", firstErr)
+	}
+
+	baseline := found[0]
+	comparison.Baseline = baseline.Name
+	for _, pokemon := range found {
+		comparison.Pokemon = append(comparison.Pokemon, entities.ComparedPokemon{
+			ID:            pokemon.ID,
+			Name:          pokemon.Name,
+			Types:         pokemon.TypeNames(),
+			Height:        pokemon.Height,
+			Weight:        pokemon.Weight,
+			BaseStats:     pokemon.BaseStats(),
+			BaseStatTotal: pokemon.BaseStatTotal(),
+		})
+		comparison.Sizes = append(comparison.Sizes, entities.SizeComparison{
+			Pokemon:     pokemon.Name,
+			HeightRatio: ratio(pokemon.Height, baseline.Height),
+			WeightRatio: ratio(pokemon.Weight, baseline.Weight),
+		})
+	}
+
+	for _, stat := range entities.StatNames {
+		comparison.Stats = append(comparison.Stats, compareStat(stat, comparison.Pokemon))
+	}
+	comparison.Stats = append(comparison.Stats, compareStat(statTotal, comparison.Pokemon))
+
+	for _, attacker := range comparison.Pokemon {
+		for _, defender := range comparison.Pokemon {
+			if attacker.ID == defender.ID {
+				continue
+			}
+			comparison.Matchups = append(comparison.Matchups, bestMatchup(chart, attacker, defender))
+		}
+	}
+
+	return comparison, nil
+}
+
+func compareStat(stat string, pokemon []entities.ComparedPokemon) entities.StatComparison {
+	comparison := entities.StatComparison{
+		Stat:    stat,
+		Values:  make(map[string]int, len(pokemon)),
+		Deltas:  make(map[string]int, len(pokemon)),
+		Winners: []string{},
+	}
+
+	value := func(p entities.ComparedPokemon) int {
+		if stat == statTotal {
+			return p.BaseStatTotal
+		}
+		return p.BaseStats[stat]
+	}
+
+	best := -1
+	base := value(pokemon[0])
+	for _, p := range pokemon {
+		v := value(p)
+		comparison.Values[p.Name] = v
+		comparison.Deltas[p.Name] = v - base
+		switch {
+		case v > best:
+			best = v
+			comparison.Winners = []string{p.Name}
+		case v == best:
+			comparison.Winners = append(comparison.Winners, p.Name)
+		}
+	}
+
+	return comparison
+}
+
+func bestMatchup(chart *entities.TypeChart, attacker, defender entities.ComparedPokemon) entities.TypeMatchup {
+	matchup := entities.TypeMatchup{
+		Attacker:   attacker.Name,
+		Defender:   defender.Name,
+		Multiplier: -1,
+	}
+
+	for _, attackType := range attacker.Types {
+		multiplier := chart.Multiplier(attackType, defender.Types)
+		if multiplier > matchup.Multiplier {
+			matchup.Multiplier = multiplier
+			matchup.BestType = attackType
+		}
+	}
+	matchup.SuperEffective = matchup.Multiplier > 1
+
+	return matchup
+}
+
+func ratio(value, base int) float64 {
+	if base == 0 {
+		return 0
+	}
+	return roundTo2(float64(value) / float64(base))
+}
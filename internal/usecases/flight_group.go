@@ -0,0 +1,173 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+)
+
+// Tope de una carga en segundo plano, incluida la escritura en cache. Cubre
+// los reintentos por defecto hacia PokeAPI (3 intentos de 10s mas las
+// esperas); sin el, una carga colgada dejaria la clave sin refrescar para siempre
+const backgroundTimeout = time.Minute
+
+// Llamada en curso compartida por todos los que piden la misma clave
+type flightCall struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+
+	// Iniciada por Go: la cache espera su resultado aunque se vayan los
+	// llamadores que se unieron despues
+	background bool
+}
+
+// flightGroup deduplica cargas concurrentes de una misma clave: N fallos de
+// cache simultaneos producen una sola llamada a PokeAPI.
+//
+// La carga se ejecuta con un contexto propio, de modo que si el primer
+// llamador cancela los demas siguen esperando el resultado. Solo se cancela
+// cuando ya no queda nadie esperando o vence el plazo del primer llamador.
+// Un panic en la carga se devuelve como error a quienes esperan
+type flightGroup struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+
+	executed   atomic.Int64
+	coalesced  atomic.Int64
+	abandoned  atomic.Int64
+	background atomic.Int64
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: make(map[string]*flightCall)}
+}
+
+// Do ejecuta fn una sola vez por clave entre los llamadores concurrentes.
+// shared indica si el resultado se obtuvo uniendose a una llamada existente
+func (g *flightGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (value interface{}, shared bool, err error) {
+	g.mutex.Lock()
+	call, shared := g.calls[key]
+	if shared {
+		call.waiters++
+		g.coalesced.Add(1)
+	} else {
+		callCtx, cancel := detachedContext(ctx)
+		call = &flightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = call
+		g.executed.Add(1)
+		go g.run(callCtx, key, call, fn)
+	}
+	g.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, shared, call.err
+	case <-ctx.Done():
+		g.leave(key, call)
+		return nil, shared, contextError(ctx.Err())
+	}
+}
+
+// Go inicia una carga en segundo plano si no hay otra en curso para la clave.
+// Nadie espera el resultado: la carga solo actualiza la cache y se cancela al
+// cumplirse backgroundTimeout
+func (g *flightGroup) Go(key string, fn func(ctx context.Context) (interface{}, error)) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, inFlight := g.calls[key]; inFlight {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+	call := &flightCall{done: make(chan struct{}), cancel: cancel, background: true}
+	g.calls[key] = call
+	g.background.Add(1)
+	go g.run(ctx, key, call, fn)
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, call *flightCall, fn func(ctx context.Context) (interface{}, error)) {
+	call.value, call.err = safeCall(ctx, key, fn)
+	call.cancel()
+
+	g.mutex.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mutex.Unlock()
+
+	close(call.done)
+}
+
+// Un llamador dejo de esperar; si era el ultimo se cancela la carga y se
+// libera la clave para que una nueva solicitud empiece de cero. Las cargas en
+// segundo plano terminan siempre, aunque se vayan los llamadores de Do que se
+// unieron a ellas
+func (g *flightGroup) leave(key string, call *flightCall) {
+	g.abandoned.Add(1)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	call.waiters--
+	if call.waiters == 0 && !call.background {
+		call.cancel()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+	}
+}
+
+// Metrics devuelve los contadores de deduplicacion
+func (g *flightGroup) Metrics() map[string]interface{} {
+	g.mutex.Lock()
+	inFlight := len(g.calls)
+	g.mutex.Unlock()
+
+	return map[string]interface{}{
+		"upstream_loads":    g.executed.Load(),
+		"coalesced_callers": g.coalesced.Load(),
+		"abandoned_callers": g.abandoned.Load(),
+		"background_loads":  g.background.Load(),
+		"in_flight":         inFlight,
+	}
+}
+
+// Contexto de la carga: no se cancela con el del llamador pero conserva su
+// plazo, si lo tiene
+func detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return context.WithCancel(detached)
+}
+
+// Ejecuta la carga convirtiendo un panic en error; la carga corre en su
+// propia goroutine, donde un panic terminaria el proceso
+func safeCall(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic en la carga de %s: %v\n%s", key, r, debug.Stack())
+			value, err = nil, fmt.Errorf("panic en la carga de %s: %v", key, r)
+		}
+	}()
+	return fn(ctx)
+}
+
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apperrors.Wrap(apperrors.ErrTimeout, "", err)
+	}
+	return err
+}
@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Espera a que la carga de la clave tenga la cantidad de llamadores indicada
+func waitForWaiters(t *testing.T, g *flightGroup, key string, waiters int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mutex.Lock()
+		call, ok := g.calls[key]
+		current := 0
+		if ok {
+			current = call.waiters
+		}
+		g.mutex.Unlock()
+		if current == waiters {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("la carga de %s no llego a %d llamadores", key, waiters)
+}
+
+func TestFlightGroupCoalescesConcurrentCallers(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	var executions atomic.Int64
+
+	load := func(ctx context.Context) (interface{}, error) {
+		executions.Add(1)
+		<-release
+		return "pikachu", nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	var sharedCount atomic.Int64
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, shared, err := g.Do(context.Background(), "pokemon:25", load)
+			if err != nil || value != "pikachu" {
+				t.Errorf("Do = %v, %v", value, err)
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+		}()
+	}
+
+	waitForWaiters(t, g, "pokemon:25", callers)
+	close(release)
+	wg.Wait()
+
+	if executions.Load() != 1 {
+		t.Fatalf("la carga se ejecuto %d veces", executions.Load())
+	}
+	if sharedCount.Load() != callers-1 {
+		t.Fatalf("%d llamadores compartieron el resultado, se esperaban %d", sharedCount.Load(), callers-1)
+	}
+}
+
+func TestFlightGroupCancelsWhenLastWaiterLeaves(t *testing.T) {
+	g := newFlightGroup()
+	cancelled := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, _, err := g.Do(ctx, "pokemon:25", func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		})
+		done <- err
+	}()
+
+	waitForWaiters(t, g, "pokemon:25", 1)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do = %v, se esperaba context.Canceled", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("la carga no se cancelo al irse el ultimo llamador")
+	}
+
+	// La clave queda libre para una carga nueva
+	value, shared, err := g.Do(context.Background(), "pokemon:25", func(ctx context.Context) (interface{}, error) {
+		return "pikachu", nil
+	})
+	if err != nil || shared || value != "pikachu" {
+		t.Fatalf("Do despues de cancelar = %v, %v, %v", value, shared, err)
+	}
+}
+
+func TestFlightGroupLeaderLeavingKeepsLoadForOthers(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-release:
+			return "pikachu", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() {
+		_, _, err := g.Do(leaderCtx, "pokemon:25", load)
+		leaderDone <- err
+	}()
+	waitForWaiters(t, g, "pokemon:25", 1)
+
+	followerDone := make(chan interface{})
+	go func() {
+		value, _, _ := g.Do(context.Background(), "pokemon:25", load)
+		followerDone <- value
+	}()
+	waitForWaiters(t, g, "pokemon:25", 2)
+
+	cancelLeader()
+	if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do del primer llamador = %v", err)
+	}
+
+	close(release)
+	if value := <-followerDone; value != "pikachu" {
+		t.Fatalf("el segundo llamador recibio %v", value)
+	}
+}
+
+func TestFlightGroupBackgroundLoadSurvivesLeavingCallers(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	finished := make(chan error, 1)
+
+	g.Go("pokemon:25", func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-release:
+			finished <- nil
+			return "pikachu", nil
+		case <-ctx.Done():
+			finished <- ctx.Err()
+			return nil, ctx.Err()
+		}
+	})
+
+	// Un llamador se une a la carga en segundo plano y se va
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		g.Do(ctx, "pokemon:25", func(ctx context.Context) (interface{}, error) {
+			t.Error("Do ejecuto una carga nueva en vez de unirse a la existente")
+			return nil, nil
+		})
+		close(done)
+	}()
+	waitForWaiters(t, g, "pokemon:25", 1)
+	cancel()
+	<-done
+
+	close(release)
+	if err := <-finished; err != nil {
+		t.Fatalf("la carga en segundo plano se cancelo: %v", err)
+	}
+}
+
+func TestFlightGroupBoundsBackgroundLoads(t *testing.T) {
+	g := newFlightGroup()
+	deadlines := make(chan time.Time, 1)
+
+	g.Go("pokemon:25", func(ctx context.Context) (interface{}, error) {
+		deadline, _ := ctx.Deadline()
+		deadlines <- deadline
+		return nil, nil
+	})
+
+	deadline := <-deadlines
+	if deadline.IsZero() || time.Until(deadline) > backgroundTimeout {
+		t.Fatalf("la carga en segundo plano no tiene plazo acotado: %v", deadline)
+	}
+}
+
+func TestFlightGroupKeepsCallerDeadline(t *testing.T) {
+	g := newFlightGroup()
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	value, _, err := g.Do(ctx, "pokemon:25", func(ctx context.Context) (interface{}, error) {
+		got, ok := ctx.Deadline()
+		return ok && got.Equal(deadline), nil
+	})
+	if err != nil || value != true {
+		t.Fatalf("la carga no conservo el plazo del llamador: %v, %v", value, err)
+	}
+}
+
+func TestFlightGroupRecoversPanics(t *testing.T) {
+	g := newFlightGroup()
+
+	_, _, err := g.Do(context.Background(), "pokemon:25", func(ctx context.Context) (interface{}, error) {
+		panic("fallo inesperado")
+	})
+	if err == nil {
+		t.Fatal("Do no devolvio error tras un panic en la carga")
+	}
+
+	value, _, err := g.Do(context.Background(), "pokemon:25", func(ctx context.Context) (interface{}, error) {
+		return "pikachu", nil
+	})
+	if err != nil || value != "pikachu" {
+		t.Fatalf("la clave no se libero tras el panic: %v, %v", value, err)
+	}
+}
@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type freshnessKey struct{}
+
+// Freshness registra si alguna respuesta de la solicitud se sirvio con datos
+// vencidos de la cache
+type Freshness struct {
+	stale atomic.Bool
+}
+
+// WithFreshness adjunta al contexto un registro de frescura para la solicitud
+func WithFreshness(ctx context.Context) (context.Context, *Freshness) {
+	freshness := &Freshness{}
+	return context.WithValue(ctx, freshnessKey{}, freshness), freshness
+}
+
+// Stale indica si se sirvio algun dato vencido
+func (f *Freshness) Stale() bool {
+	return f.stale.Load()
+}
+
+func markStale(ctx context.Context) {
+	if freshness, ok := ctx.Value(freshnessKey{}).(*Freshness); ok {
+		freshness.stale.Store(true)
+	}
+}
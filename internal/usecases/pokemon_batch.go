@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+// GetPokemonByRef resuelve una referencia que puede ser un ID numerico o un nombre
+func (uc *PokemonUseCase) GetPokemonByRef(ctx context.Context, ref string) (*entities.Pokemon, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return uc.GetPokemonByID(ctx, id)
+	}
+	return uc.GetPokemonByName(ctx, ref)
+}
+
+// GetPokemonBatch obtiene varias referencias en paralelo con un maximo de
+// workers simultaneos. Los resultados y errores conservan el orden de refs
+func (uc *PokemonUseCase) GetPokemonBatch(ctx context.Context, refs []string, workers int) ([]*entities.Pokemon, []error) {
+	results := make([]*entities.Pokemon, len(refs))
+	errs := make([]error, len(refs))
+
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(refs) {
+		workers = len(refs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = uc.GetPokemonByRef(ctx, refs[i])
+			}
+		}()
+	}
+
+	for i := range refs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
@@ -2,163 +2,342 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
-
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
 	"github.com/gerardstrujills/backend/internal/domain/entities"
 	"github.com/gerardstrujills/backend/internal/domain/repositories"
 	"github.com/gerardstrujills/backend/internal/domain/services"
 )
 
+// Detalles de la pagina de busqueda que se piden a la vez
+const searchWorkers = 8
+
 type PokemonUseCase struct {
-	pokemonRepo  repositories.PokemonRepository
-	cacheService services.CacheService
+	pokemonRepo repositories.PokemonRepository
+	searchIndex services.SearchIndex
+	flights     *flightGroup
+
+	// Una cache tipada por espacio de nombres de claves
+	pokemonByID   *services.TypedCache[*entities.Pokemon]
+	pokemonByName *services.TypedCache[*entities.Pokemon]
+	speciesByID   *services.TypedCache[*entities.Species]
+	speciesByName *services.TypedCache[*entities.Species]
+	evolutions    *services.TypedCache[*entities.EvolutionChain]
+	lists         *services.TypedCache[*entities.PokemonList]
+	searches      *services.TypedCache[*entities.SearchResult]
 }
 
-func NewPokemonUseCase(pokemonRepo repositories.PokemonRepository, cacheService services.CacheService) *PokemonUseCase {
+// NewPokemonUseCase crea el caso de uso; ttlPolicy fija el TTL de cada
+// espacio de nombres de la cache (nil usa el TTL por defecto del backend)
+func NewPokemonUseCase(pokemonRepo repositories.PokemonRepository, cacheService services.CacheService, searchIndex services.SearchIndex, ttlPolicy *services.TTLPolicy) *PokemonUseCase {
 	return &PokemonUseCase{
-		pokemonRepo:  pokemonRepo,
-		cacheService: cacheService,
+		pokemonRepo: pokemonRepo,
+		searchIndex: searchIndex,
+		flights:     newFlightGroup(),
+
+		pokemonByID:   newTypedCache[*entities.Pokemon](cacheService, ttlPolicy, "pokemon:id"),
+		pokemonByName: newTypedCache[*entities.Pokemon](cacheService, ttlPolicy, "pokemon:name"),
+		speciesByID:   newTypedCache[*entities.Species](cacheService, ttlPolicy, "pokemon:species:id"),
+		speciesByName: newTypedCache[*entities.Species](cacheService, ttlPolicy, "pokemon:species:name"),
+		evolutions:    newTypedCache[*entities.EvolutionChain](cacheService, ttlPolicy, "pokemon:evolution"),
+		lists:         newTypedCache[*entities.PokemonList](cacheService, ttlPolicy, "pokemon:list"),
+		searches:      newTypedCache[*entities.SearchResult](cacheService, ttlPolicy, "pokemon:search"),
 	}
 }
 
+// Cache tipada con codec JSON y el TTL que la politica asigna al espacio de nombres
+func newTypedCache[T any](cacheService services.CacheService, ttlPolicy *services.TTLPolicy, namespace string) *services.TypedCache[T] {
+	return services.NewTypedCache[T](cacheService, namespace, services.JSONCodec[T]{}, ttlPolicy.Options(namespace+":")...)
+}
+
 // GetPokemonByID obtiene un Pokemon por ID con cache
 func (uc *PokemonUseCase) GetPokemonByID(ctx context.Context, id int) (*entities.Pokemon, error) {
-	cacheKey := fmt.Sprintf("pokemon:id:%d", id)
+	return loadCached(ctx, uc, uc.pokemonByID, strconv.Itoa(id), func(ctx context.Context) (*entities.Pokemon, error) {
+		pokemon, err := uc.pokemonRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo obtener pokemon por ID: %d: %w", id, err)
+		}
+		return pokemon, nil
+	})
+}
 
-	// Intentar obtener del cache primero
-	if cachedData, found := uc.cacheService.Get(ctx, cacheKey); found {
-		if pokemon, ok := cachedData.(*entities.Pokemon); ok {
-			return pokemon, nil
+// GetPokemonByName obtiene un Pokemon por nombre con cache
+func (uc *PokemonUseCase) GetPokemonByName(ctx context.Context, name string) (*entities.Pokemon, error) {
+	return loadCached(ctx, uc, uc.pokemonByName, strings.ToLower(name), func(ctx context.Context) (*entities.Pokemon, error) {
+		pokemon, err := uc.pokemonRepo.GetByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("no pude obtener el pokemon por su nombre: %s: %w", name, err)
 		}
-	}
+		return pokemon, nil
+	})
+}
 
-	// Si no esta en cache, obtener del repositorio
-	pokemon, err := uc.pokemonRepo.GetByID(ctx, id)
+// GetSpeciesByID obtiene la especie de un Pokemon por su ID con cache. La
+// especie sale del propio Pokemon porque las formas no comparten ID con ella
+func (uc *PokemonUseCase) GetSpeciesByID(ctx context.Context, pokemonID int) (*entities.Species, error) {
+	pokemon, err := uc.GetPokemonByID(ctx, pokemonID)
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo obtener pokemon por ID: %d: %w", id, err)
+		return nil, err
 	}
+	return uc.getSpecies(ctx, pokemon.SpeciesID())
+}
 
-	// Guardar en cache
-	if err := uc.cacheService.Set(ctx, cacheKey, pokemon); err != nil {
-		// Registrar error pero no fallar en la solicitud
-		fmt.Printf("no se pudo almacenar en cache el pokemon: %d: %v\n", id, err)
+// GetSpeciesByName obtiene la especie de un Pokemon por su nombre con cache.
+// Si no hay un Pokemon con ese nombre se busca como nombre de especie, que
+// puede diferir del de sus formas (deoxys frente a deoxys-normal)
+func (uc *PokemonUseCase) GetSpeciesByName(ctx context.Context, name string) (*entities.Species, error) {
+	pokemon, err := uc.GetPokemonByName(ctx, name)
+	if err == nil {
+		return uc.getSpecies(ctx, pokemon.SpeciesID())
+	}
+	if !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, err
 	}
 
-	return pokemon, nil
+	return loadCached(ctx, uc, uc.speciesByName, strings.ToLower(name), func(ctx context.Context) (*entities.Species, error) {
+		species, err := uc.pokemonRepo.GetSpeciesByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo obtener la especie por nombre: %s: %w", name, err)
+		}
+		return species, nil
+	})
 }
 
-// GetPokemonByName obtiene un Pokemon por nombre con cache
-func (uc *PokemonUseCase) GetPokemonByName(ctx context.Context, name string) (*entities.Pokemon, error) {
-	cacheKey := fmt.Sprintf("pokemon:name:%s", strings.ToLower(name))
-
-	// Intentar obtener del cache primero
-	if cachedData, found := uc.cacheService.Get(ctx, cacheKey); found {
-		if pokemon, ok := cachedData.(*entities.Pokemon); ok {
-			return pokemon, nil
+// Especie por su propio ID, con cache
+func (uc *PokemonUseCase) getSpecies(ctx context.Context, id int) (*entities.Species, error) {
+	return loadCached(ctx, uc, uc.speciesByID, strconv.Itoa(id), func(ctx context.Context) (*entities.Species, error) {
+		species, err := uc.pokemonRepo.GetSpeciesByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo obtener la especie por ID: %d: %w", id, err)
 		}
-	}
+		return species, nil
+	})
+}
 
-	// Si no esta en cache, obtener del repositorio
-	pokemon, err := uc.pokemonRepo.GetByName(ctx, name)
+// GetEvolutionChain obtiene la cadena evolutiva de un Pokemon siguiendo
+// pokemon -> especie -> evolution_chain, con cache de cada paso. La especie
+// sale del propio Pokemon porque las formas no comparten ID con ella
+func (uc *PokemonUseCase) GetEvolutionChain(ctx context.Context, pokemonID int) (*entities.EvolutionChain, error) {
+	pokemon, err := uc.GetPokemonByID(ctx, pokemonID)
 	if err != nil {
-		return nil, fmt.Errorf("no pude obtener el pokemon por su nombre: %s: %w", name, err)
+		return nil, err
 	}
 
-	// Guardar en cache
-	if err := uc.cacheService.Set(ctx, cacheKey, pokemon); err != nil {
-		fmt.Printf("no se pudo almacenar en cache el pokemon: %s: %v\n", name, err)
+	species, err := uc.getSpecies(ctx, pokemon.SpeciesID())
+	if err != nil {
+		return nil, err
 	}
+	if species.EvolutionChainID == 0 {
+		return nil, apperrors.New(apperrors.ErrNotFound, "El Pokemon no tiene cadena evolutiva")
+	}
+
+	chainID := species.EvolutionChainID
 
-	return pokemon, nil
+	return loadCached(ctx, uc, uc.evolutions, strconv.Itoa(chainID), func(ctx context.Context) (*entities.EvolutionChain, error) {
+		chain, err := uc.pokemonRepo.GetEvolutionChain(ctx, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo obtener la cadena evolutiva: %d: %w", chainID, err)
+		}
+		return chain, nil
+	})
 }
 
 // GetPokemonList obtiene una lista paginada de Pokemon con cache
 func (uc *PokemonUseCase) GetPokemonList(ctx context.Context, limit, offset int) (*entities.PokemonList, error) {
-	cacheKey := fmt.Sprintf("pokemon:list:%d:%d", limit, offset)
+	cacheID := fmt.Sprintf("%d:%d", limit, offset)
 
-	// Intentar obtener del cache primero
-	if cachedData, found := uc.cacheService.Get(ctx, cacheKey); found {
-		if pokemonList, ok := cachedData.(*entities.PokemonList); ok {
-			return pokemonList, nil
+	return loadCached(ctx, uc, uc.lists, cacheID, func(ctx context.Context) (*entities.PokemonList, error) {
+		pokemonList, err := uc.pokemonRepo.GetList(ctx, limit, offset)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo obtener la lista de pokemon: %w", err)
 		}
-	}
+		return pokemonList, nil
+	})
+}
+
+// SearchPokemonByTitle busca Pokemon por titulo/nombre con cache. Los
+// candidatos salen del indice en memoria; solo se piden detalles de la pagina,
+// en paralelo y conservando el orden de relevancia. Los que fallan se informan
+// en Errors y la pagina incompleta no se guarda en cache
+func (uc *PokemonUseCase) SearchPokemonByTitle(ctx context.Context, title string, limit, offset int) (*entities.SearchResult, error) {
+	searchTerm := strings.ToLower(title)
+	cacheID := fmt.Sprintf("%s:%d:%d", searchTerm, limit, offset)
 
-	// Si no esta en cache, obtener del repositorio
-	pokemonList, err := uc.pokemonRepo.GetList(ctx, limit, offset)
+	return loadCached(ctx, uc, uc.searches, cacheID, func(ctx context.Context) (*entities.SearchResult, error) {
+		candidates, err := uc.searchIndex.Search(ctx, searchTerm)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo buscar Pokémon por titulo: %s: %w", title, err)
+		}
+
+		// Aplicar paginación a candidatos
+		searchResult := &entities.SearchResult{
+			Total:   len(candidates),
+			Results: []*entities.Pokemon{},
+			Offset:  offset,
+		}
+		start := offset
+		end := min(offset+limit, len(candidates))
+		if end <= start {
+			return searchResult, nil
+		}
+		searchResult.First, searchResult.Last = &candidates[start], &candidates[end-1]
+
+		// Usa la cache individual de cada Pokemon
+		names := make([]string, 0, end-start)
+		for _, candidate := range candidates[start:end] {
+			names = append(names, candidate.Name)
+		}
+		results, errs := uc.GetPokemonBatch(ctx, names, searchWorkers)
+		for i, pokemon := range results {
+			if errs[i] != nil {
+				searchResult.Errors = append(searchResult.Errors, entities.SearchError{
+					Name:    names[i],
+					Code:    apperrors.CodeOf(errs[i]),
+					Message: apperrors.MessageOf(errs[i]),
+				})
+				continue
+			}
+			searchResult.Results = append(searchResult.Results, pokemon)
+		}
+
+		// Si no se obtuvo ninguno no hay resultado parcial que mostrar
+		if len(searchResult.Results) == 0 && len(searchResult.Errors) > 0 {
+			return nil, fmt.Errorf("no se pudo obtener ningun resultado de la busqueda: %s: %w", title, errs[0])
+		}
+
+		return searchResult, nil
+	})
+}
+
+// SearchPokemonFrom devuelve la pagina de la busqueda que sigue al candidato
+// position, o la que lo precede con backward. Al partir de una posicion y no
+// de un desplazamiento, la pagina no se corre si el indice cambia entre
+// solicitudes
+func (uc *PokemonUseCase) SearchPokemonFrom(ctx context.Context, title string, position entities.SearchMatch, backward bool, limit int) (*entities.SearchResult, error) {
+	candidates, err := uc.searchIndex.Search(ctx, strings.ToLower(title))
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo obtener la lista de pokemon: %w", err)
+		return nil, fmt.Errorf("no se pudo buscar Pokémon por titulo: %s: %w", title, err)
 	}
 
-	// Guardar en cache
-	if err := uc.cacheService.Set(ctx, cacheKey, pokemonList); err != nil {
-		fmt.Printf("no se pudo almacenar en cache el pokemon list: %v\n", err)
+	if backward {
+		end := sort.Search(len(candidates), func(i int) bool {
+			return !candidates[i].RanksBefore(position)
+		})
+		// La posicion ahora es la primera: no hay pagina anterior
+		if end == 0 {
+			return &entities.SearchResult{Total: len(candidates), Results: []*entities.Pokemon{}}, nil
+		}
+		start := max(end-limit, 0)
+		return uc.SearchPokemonByTitle(ctx, title, end-start, start)
 	}
 
-	return pokemonList, nil
+	start := sort.Search(len(candidates), func(i int) bool {
+		return position.RanksBefore(candidates[i])
+	})
+	return uc.SearchPokemonByTitle(ctx, title, limit, start)
 }
 
-// SearchPokemonByTitle busca Pokemon por titulo/nombre con cache
-func (uc *PokemonUseCase) SearchPokemonByTitle(ctx context.Context, title string, limit, offset int) ([]*entities.Pokemon, error) {
-	searchTerm := strings.ToLower(title)
-	cacheKey := fmt.Sprintf("pokemon:search:%s:%d:%d", searchTerm, limit, offset)
+// GetPokemonListFrom devuelve la pagina del listado que sigue al Pokemon con
+// ID id, o la que lo precede con backward, junto con su posicion. Sale del
+// listado completo del indice, por lo que avanzar a paginas profundas no
+// cuesta mas y la pagina no se corre si PokeAPI agrega Pokemon
+func (uc *PokemonUseCase) GetPokemonListFrom(ctx context.Context, id int, backward bool, limit int) (*entities.PokemonList, int, error) {
+	listing, err := uc.searchIndex.Listing(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("no se pudo obtener la lista de pokemon: %w", err)
+	}
 
-	// Intentar obtener del cache primero
-	if cachedData, found := uc.cacheService.Get(ctx, cacheKey); found {
-		if pokemonList, ok := cachedData.([]*entities.Pokemon); ok {
-			return pokemonList, nil
-		}
+	var start, end int
+	if backward {
+		end = sort.Search(len(listing), func(i int) bool {
+			return listing[i].ID() >= id
+		})
+		start = max(end-limit, 0)
+	} else {
+		start = sort.Search(len(listing), func(i int) bool {
+			return listing[i].ID() > id
+		})
+		end = min(start+limit, len(listing))
 	}
 
-	// Cache adicional para candidatos de busqueda (evita re-filtrar)
-	candidatesCacheKey := fmt.Sprintf("pokemon:search_candidates:%s", searchTerm)
-	var candidates []string
+	return &entities.PokemonList{
+		Count:   len(listing),
+		Results: slices.Clone(listing[start:end]),
+	}, start, nil
+}
+
+// Metrics expone los contadores de deduplicacion de cargas
+func (uc *PokemonUseCase) Metrics() map[string]interface{} {
+	return uc.flights.Metrics()
+}
+
+// loadCached busca id en la cache tipada y, si no esta, lo carga con fetch.
+// Las cargas concurrentes de la misma clave se agrupan en una sola llamada y
+// solo esa llamada guarda el resultado en cache.
+//
+// Un elemento vencido (pasado el TTL blando) se devuelve de inmediato, se
+// marca la solicitud como servida con datos vencidos y se refresca en segundo
+// plano. Si PokeAPI falla, el dato vencido se sigue sirviendo hasta el TTL duro.
+// Los resultados parciales (partialResult incompleto) se devuelven sin guardarlos
+//
+// El refresco es condicional con los validadores (ETag/Last-Modified) del dato
+// vencido: si PokeAPI responde que no cambio, solo se renueva su TTL
+func loadCached[T any](ctx context.Context, uc *PokemonUseCase, cache *services.TypedCache[T], id string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	cacheKey := cache.Key(id)
+	entry, found := cache.GetEntry(ctx, id)
 
-	if cachedCandidates, found := uc.cacheService.Get(ctx, candidatesCacheKey); found {
-		if candidatesList, ok := cachedCandidates.([]string); ok {
-			candidates = candidatesList
+	load := func(ctx context.Context) (interface{}, error) {
+		// Cada carga lleva su propia revalidacion; sin dato en cache va vacia
+		var cached entities.Validators
+		if found {
+			cached = entry.Validators
 		}
-	}
+		ctx, revalidation := repositories.WithRevalidation(ctx, cached)
 
-	// Si no hay candidatos en cache, buscar
-	if len(candidates) == 0 {
-		pokemonList, err := uc.pokemonRepo.SearchByTitle(ctx, title, limit, offset)
+		value, err := fetch(ctx)
+		if found && errors.Is(err, repositories.ErrNotModified) {
+			value, err = entry.Value, nil
+		}
 		if err != nil {
-			return nil, fmt.Errorf("no se pudo buscar Pokémon por titulo: %s: %w", title, err)
+			return nil, err
 		}
 
-		// Cachear resultado final
-		if err := uc.cacheService.Set(ctx, cacheKey, pokemonList); err != nil {
-			fmt.Printf("no se pudo almacenar en cache el pokemon search: %v\n", err)
+		if partial, ok := any(value).(partialResult); ok && !partial.Complete() {
+			return value, nil
 		}
 
-		return pokemonList, nil
-	}
-
-	// Usar candidatos cacheados para paginación eficiente
-	var results []*entities.Pokemon
-	start := offset
-	end := offset + limit
-	if start >= len(candidates) {
-		return results, nil
-	}
-	if end > len(candidates) {
-		end = len(candidates)
+		// Guardar en cache; un error no hace fallar la solicitud
+		if err := cache.Set(ctx, id, value, services.WithValidators(revalidation.Received())); err != nil {
+			fmt.Printf("no se pudo almacenar en cache: %s: %v\n", cacheKey, err)
+		}
+		return value, nil
 	}
 
-	for i := start; i < end; i++ {
-		pokemon, err := uc.GetPokemonByName(ctx, candidates[i]) // Usa cache individual
-		if err != nil {
-			continue
+	// Intentar obtener del cache primero
+	if found {
+		if entry.IsExpired() {
+			markStale(ctx)
+			uc.flights.Go(cacheKey, load)
 		}
-		results = append(results, pokemon)
+		return entry.Value, nil
 	}
 
-	// Cachear resultado paginado
-	if err := uc.cacheService.Set(ctx, cacheKey, results); err != nil {
-		fmt.Printf("no se pudo almacenar en cache el pokemon search: %v\n", err)
+	value, _, err := uc.flights.Do(ctx, cacheKey, load)
+	if err != nil {
+		var zero T
+		return zero, err
 	}
 
-	return results, nil
+	return value.(T), nil
+}
+
+// Resultado que puede quedar incompleto si falla parte de la carga
+type partialResult interface {
+	Complete() bool
 }
@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+)
+
+const (
+	maxTeamSize    = 6
+	teamWorkers    = 3
+	maxSuggestions = 5
+)
+
+type TeamUseCase struct {
+	pokemonUseCase *PokemonUseCase
+	typeUseCase    *TypeUseCase
+}
+
+func NewTeamUseCase(pokemonUseCase *PokemonUseCase, typeUseCase *TypeUseCase) *TeamUseCase {
+	return &TeamUseCase{
+		pokemonUseCase: pokemonUseCase,
+		typeUseCase:    typeUseCase,
+	}
+}
+
+// AnalyzeTeam analiza cobertura, debilidades y estadisticas de un equipo.
+// refs acepta IDs o nombres; los miembros se obtienen en paralelo
+func (uc *TeamUseCase) AnalyzeTeam(ctx context.Context, refs []string) (*entities.TeamAnalysis, error) {
+	if len(refs) == 0 || len(refs) > maxTeamSize {
+		return nil, apperrors.New(apperrors.ErrInvalidInput, fmt.Sprintf("El equipo debe tener entre 1 y %d Pokemon", maxTeamSize))
+	}
+
+	chart, err := uc.typeUseCase.GetTypeChart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, errs := uc.pokemonUseCase.GetPokemonBatch(ctx, refs, teamWorkers)
+	for i, err := range errs {
+		if err != nil {
+			return nil, memberError(refs[i], err)
+		}
+	}
+
+	analysis := &entities.TeamAnalysis{
+		Members: make([]entities.TeamMember, 0, len(members)),
+	}
+
+	teamTypes := make(map[string]bool)
+	profiles := make([]*entities.DefensiveProfile, 0, len(members))
+	for _, pokemon := range members {
+		types := pokemon.TypeNames()
+		for _, t := range types {
+			teamTypes[t] = true
+		}
+		profiles = append(profiles, chart.DefensiveProfile(types))
+		analysis.Members = append(analysis.Members, entities.TeamMember{
+			ID:            pokemon.ID,
+			Name:          pokemon.Name,
+			Types:         types,
+			BaseStatTotal: pokemon.BaseStatTotal(),
+		})
+	}
+
+	analysis.Coverage = teamCoverage(chart, teamTypes)
+	analysis.Defense, analysis.SharedWeaknesses, analysis.UnresolvedWeaknesses = teamDefense(profiles)
+	analysis.Stats = teamStats(members)
+	analysis.MissingTypes, analysis.Suggestions = teamSuggestions(chart, teamTypes, analysis.UnresolvedWeaknesses, analysis.Coverage.NotCovered)
+
+	return analysis, nil
+}
+
+// Conserva el tipo de error e indica que miembro fallo
+func memberError(ref string, err error) error {
+	if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+		return apperrors.Wrap(apperrors.ErrNotFound, fmt.Sprintf("Pokemon no encontrado: %s", ref), err)
+	}
+	return fmt.Errorf("no se pudo obtener el miembro del equipo: %s: %w", ref, err)
+}
+
+// Tipos a los que el equipo pega super efectivo con sus propios tipos
+func teamCoverage(chart *entities.TypeChart, teamTypes map[string]bool) entities.TeamCoverage {
+	coverage := entities.TeamCoverage{
+		SuperEffective: []string{},
+		NotCovered:     []string{},
+	}
+
+	for _, defender := range entities.AllTypes {
+		covered := false
+		for attacker := range teamTypes {
+			if chart.Effectiveness(attacker, defender) > 1 {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			coverage.SuperEffective = append(coverage.SuperEffective, defender)
+		} else {
+			coverage.NotCovered = append(coverage.NotCovered, defender)
+		}
+	}
+
+	return coverage
+}
+
+// Debilidades compartidas (2 o mas miembros) y no resueltas (algun miembro
+// debil y ninguno que resista o sea inmune)
+func teamDefense(profiles []*entities.DefensiveProfile) ([]entities.TeamTypeDefense, []string, []string) {
+	defense := make([]entities.TeamTypeDefense, 0, len(entities.AllTypes))
+	shared := []string{}
+	unresolved := []string{}
+
+	for _, attacker := range entities.AllTypes {
+		report := entities.TeamTypeDefense{Type: attacker}
+		for _, profile := range profiles {
+			multiplier := profile.Multipliers[attacker]
+			switch {
+			case multiplier == 0:
+				report.Immune++
+			case multiplier < 1:
+				report.Resist++
+			case multiplier > 1:
+				report.Weak++
+			default:
+				report.Neutral++
+			}
+		}
+
+		if report.Weak >= 2 {
+			shared = append(shared, attacker)
+		}
+		if report.Weak > 0 && report.Resist+report.Immune == 0 {
+			unresolved = append(unresolved, attacker)
+		}
+		defense = append(defense, report)
+	}
+
+	return defense, shared, unresolved
+}
+
+func teamStats(members []*entities.Pokemon) entities.TeamStats {
+	stats := entities.TeamStats{
+		Totals:   make(map[string]int, len(entities.StatNames)),
+		Averages: make(map[string]float64, len(entities.StatNames)),
+	}
+
+	for _, pokemon := range members {
+		for name, value := range pokemon.BaseStats() {
+			stats.Totals[name] += value
+		}
+		stats.BaseStatTotal += pokemon.BaseStatTotal()
+	}
+
+	count := float64(len(members))
+	for name, total := range stats.Totals {
+		stats.Averages[name] = roundTo2(float64(total) / count)
+	}
+	stats.AverageBaseStatTotal = roundTo2(float64(stats.BaseStatTotal) / count)
+
+	return stats
+}
+
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// Sugiere tipos ausentes priorizando los que resisten debilidades no
+// resueltas y los que cubren tipos a los que el equipo no pega fuerte
+func teamSuggestions(chart *entities.TypeChart, teamTypes map[string]bool, unresolved, notCovered []string) ([]string, []entities.TypeSuggestion) {
+	missing := []string{}
+	suggestions := []entities.TypeSuggestion{}
+
+	for _, candidate := range entities.AllTypes {
+		if teamTypes[candidate] {
+			continue
+		}
+		missing = append(missing, candidate)
+
+		suggestion := entities.TypeSuggestion{
+			Type:    candidate,
+			Resists: []string{},
+			Covers:  []string{},
+		}
+		for _, attacker := range unresolved {
+			if chart.Effectiveness(attacker, candidate) < 1 {
+				suggestion.Resists = append(suggestion.Resists, attacker)
+			}
+		}
+		for _, defender := range notCovered {
+			if chart.Effectiveness(candidate, defender) > 1 {
+				suggestion.Covers = append(suggestion.Covers, defender)
+			}
+		}
+
+		// Resolver una debilidad pesa mas que ampliar la cobertura
+		suggestion.Score = 2*len(suggestion.Resists) + len(suggestion.Covers)
+		if suggestion.Score > 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return missing, suggestions
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/repositories"
+)
+
+// TypeUseCase mantiene en memoria la matriz de efectividad de los 18 tipos
+type TypeUseCase struct {
+	typeRepo       repositories.TypeRepository
+	pokemonUseCase *PokemonUseCase
+
+	// Protege solo la lectura y escritura de la matriz ya cargada; la descarga
+	// se deduplica con flights sin retener el mutex
+	mutex   sync.Mutex
+	types   map[string]*entities.PokemonType
+	chart   *entities.TypeChart
+	flights *flightGroup
+}
+
+func NewTypeUseCase(typeRepo repositories.TypeRepository, pokemonUseCase *PokemonUseCase) *TypeUseCase {
+	return &TypeUseCase{
+		typeRepo:       typeRepo,
+		pokemonUseCase: pokemonUseCase,
+		flights:        newFlightGroup(),
+	}
+}
+
+// GetTypeChart devuelve la matriz de efectividad, cargandola la primera vez.
+// Las llamadas concurrentes comparten una sola descarga y cada una deja de
+// esperar cuando vence su propio contexto. Si la carga falla se reintenta en
+// la siguiente llamada
+func (uc *TypeUseCase) GetTypeChart(ctx context.Context) (*entities.TypeChart, error) {
+	chart, _ := uc.loaded()
+	if chart != nil {
+		return chart, nil
+	}
+
+	value, _, err := uc.flights.Do(ctx, "type-chart", func(ctx context.Context) (interface{}, error) {
+		// Otra carga pudo terminar justo antes de esta
+		if chart, _ := uc.loaded(); chart != nil {
+			return chart, nil
+		}
+
+		types, err := uc.loadTypes(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		list := make([]*entities.PokemonType, 0, len(types))
+		for _, name := range entities.AllTypes {
+			list = append(list, types[name])
+		}
+		chart := entities.NewTypeChart(list)
+
+		uc.mutex.Lock()
+		uc.types = types
+		uc.chart = chart
+		uc.mutex.Unlock()
+		return chart, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*entities.TypeChart), nil
+}
+
+// Matriz y tipos ya cargados, o nil si aun no se cargaron
+func (uc *TypeUseCase) loaded() (*entities.TypeChart, map[string]*entities.PokemonType) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	return uc.chart, uc.types
+}
+
+// Tipos por nombre, cargando la matriz si hace falta
+func (uc *TypeUseCase) loadedTypes(ctx context.Context) (*entities.TypeChart, map[string]*entities.PokemonType, error) {
+	if _, err := uc.GetTypeChart(ctx); err != nil {
+		return nil, nil, err
+	}
+	chart, types := uc.loaded()
+	return chart, types, nil
+}
+
+// ListTypes devuelve los 18 tipos con sus relaciones de daño
+func (uc *TypeUseCase) ListTypes(ctx context.Context) ([]*entities.PokemonType, error) {
+	_, types, err := uc.loadedTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*entities.PokemonType, 0, len(entities.AllTypes))
+	for _, name := range entities.AllTypes {
+		list = append(list, types[name])
+	}
+	return list, nil
+}
+
+// GetType devuelve un tipo con sus multiplicadores ofensivos y defensivos
+func (uc *TypeUseCase) GetType(ctx context.Context, name string) (*entities.TypeDetail, error) {
+	chart, types, err := uc.loadedTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.ToLower(name)
+	pokemonType, ok := types[name]
+	if !ok {
+		return nil, apperrors.New(apperrors.ErrNotFound, "Tipo no encontrado")
+	}
+
+	return &entities.TypeDetail{
+		Type:               pokemonType,
+		AttackMultipliers:  chart.AttackMultipliers(name),
+		DefenseMultipliers: chart.DefenseMultipliers(name),
+	}, nil
+}
+
+// GetPokemonWeaknesses calcula los multiplicadores combinados que recibe un Pokemon
+func (uc *TypeUseCase) GetPokemonWeaknesses(ctx context.Context, id int) (*entities.PokemonWeaknesses, error) {
+	pokemon, err := uc.pokemonUseCase.GetPokemonByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, err := uc.GetTypeChart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PokemonWeaknesses{
+		ID:               pokemon.ID,
+		Name:             pokemon.Name,
+		DefensiveProfile: *chart.DefensiveProfile(pokemon.TypeNames()),
+	}, nil
+}
+
+// Descarga los 18 tipos en paralelo; falla si alguno no se pudo obtener
+func (uc *TypeUseCase) loadTypes(ctx context.Context) (map[string]*entities.PokemonType, error) {
+	type result struct {
+		pokemonType *entities.PokemonType
+		name        string
+		err         error
+	}
+
+	results := make(chan result, len(entities.AllTypes))
+	for _, name := range entities.AllTypes {
+		go func(name string) {
+			pokemonType, err := uc.typeRepo.GetType(ctx, name)
+			results <- result{pokemonType: pokemonType, name: name, err: err}
+		}(name)
+	}
+
+	types := make(map[string]*entities.PokemonType, len(entities.AllTypes))
+	var firstErr error
+	for range entities.AllTypes {
+		r := <-results
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("no se pudo obtener el tipo: %s: %w", r.name, r.err)
+			}
+			continue
+		}
+		types[r.name] = r.pokemonType
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return types, nil
+}
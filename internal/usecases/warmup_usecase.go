@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gerardstrujills/backend/internal/domain/apperrors"
+	"github.com/gerardstrujills/backend/internal/domain/entities"
+	"github.com/gerardstrujills/backend/internal/domain/services"
+)
+
+const (
+	warmupMaxRetries  = 5
+	warmupBaseBackoff = time.Second
+	warmupMaxBackoff  = 30 * time.Second
+)
+
+// Tarea de precalentamiento con un nombre para los registros
+type warmupTask struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// WarmupUseCase precarga en segundo plano el indice de nombres, la tabla de
+// tipos y los primeros Pokemon por ID, y marca la aplicacion como lista al
+// terminar
+type WarmupUseCase struct {
+	pokemonUseCase *PokemonUseCase
+	typeUseCase    *TypeUseCase
+	searchIndex    services.SearchIndex
+	pokemonCount   int
+	workers        int
+
+	mutex      sync.Mutex
+	status     entities.WarmupStatus
+	pauseUntil time.Time
+}
+
+// NewWarmupUseCase crea el precalentador. pokemonCount es la cantidad de
+// Pokemon a precargar desde el #1 y workers el limite de cargas en paralelo
+func NewWarmupUseCase(pokemonUseCase *PokemonUseCase, typeUseCase *TypeUseCase, searchIndex services.SearchIndex, pokemonCount, workers int) *WarmupUseCase {
+	return &WarmupUseCase{
+		pokemonUseCase: pokemonUseCase,
+		typeUseCase:    typeUseCase,
+		searchIndex:    searchIndex,
+		pokemonCount:   max(pokemonCount, 0),
+		workers:        max(workers, 1),
+		status:         entities.WarmupStatus{State: entities.WarmupPending},
+	}
+}
+
+// Start inicia el precalentamiento en segundo plano
+func (uc *WarmupUseCase) Start(ctx context.Context) {
+	tasks := uc.tasks()
+
+	now := time.Now()
+	uc.mutex.Lock()
+	uc.status.State = entities.WarmupRunning
+	uc.status.Total = len(tasks)
+	uc.status.StartedAt = &now
+	uc.mutex.Unlock()
+
+	go uc.run(ctx, tasks)
+}
+
+// Disable marca la aplicacion como lista sin precalentar
+func (uc *WarmupUseCase) Disable() {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	uc.status.State = entities.WarmupDisabled
+	uc.status.Ready = true
+}
+
+// Status devuelve el progreso actual
+func (uc *WarmupUseCase) Status() entities.WarmupStatus {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	return uc.status
+}
+
+// Ready indica si el precalentamiento termino
+func (uc *WarmupUseCase) Ready() bool {
+	return uc.Status().Ready
+}
+
+// Metrics expone el progreso en /metrics
+func (uc *WarmupUseCase) Metrics() map[string]interface{} {
+	status := uc.Status()
+	return map[string]interface{}{
+		"state":        status.State,
+		"ready":        status.Ready,
+		"total":        status.Total,
+		"completed":    status.Completed,
+		"failed":       status.Failed,
+		"rate_limited": status.RateLimited,
+	}
+}
+
+// El indice y los tipos van primero: son pocas solicitudes y los usan la
+// busqueda y el analisis de equipos
+func (uc *WarmupUseCase) tasks() []warmupTask {
+	tasks := []warmupTask{
+		{name: "indice de nombres", run: uc.searchIndex.Refresh},
+		{name: "tipos", run: func(ctx context.Context) error {
+			_, err := uc.typeUseCase.GetTypeChart(ctx)
+			return err
+		}},
+	}
+
+	for id := 1; id <= uc.pokemonCount; id++ {
+		tasks = append(tasks, warmupTask{
+			name: fmt.Sprintf("pokemon %d", id),
+			run: func(ctx context.Context) error {
+				_, err := uc.pokemonUseCase.GetPokemonByID(ctx, id)
+				return err
+			},
+		})
+	}
+
+	return tasks
+}
+
+func (uc *WarmupUseCase) run(ctx context.Context, tasks []warmupTask) {
+	queue := make(chan warmupTask)
+	var wg sync.WaitGroup
+
+	for range uc.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				uc.runTask(ctx, task)
+			}
+		}()
+	}
+
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- task
+	}
+	close(queue)
+	wg.Wait()
+
+	now := time.Now()
+	uc.mutex.Lock()
+	uc.status.State = entities.WarmupDone
+	uc.status.Ready = true
+	uc.status.FinishedAt = &now
+	status := uc.status
+	uc.mutex.Unlock()
+
+	log.Printf("precalentamiento de cache terminado: %d de %d tareas, %d fallidas", status.Completed, status.Total, status.Failed)
+}
+
+// Ejecuta una tarea reintentando con espera creciente cuando PokeAPI limita
+// las solicitudes. La pausa se comparte para que todos los workers esperen
+func (uc *WarmupUseCase) runTask(ctx context.Context, task warmupTask) {
+	backoff := warmupBaseBackoff
+
+	for attempt := 0; ; attempt++ {
+		if err := uc.waitPause(ctx); err != nil {
+			uc.finishTask(task, err)
+			return
+		}
+
+		err := task.run(ctx)
+		if err == nil || !errors.Is(err, apperrors.ErrUpstreamRateLimited) || attempt == warmupMaxRetries {
+			uc.finishTask(task, err)
+			return
+		}
+
+		uc.pause(backoff)
+		backoff = min(backoff*2, warmupMaxBackoff)
+	}
+}
+
+func (uc *WarmupUseCase) finishTask(task warmupTask, err error) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	if err != nil {
+		uc.status.Failed++
+		log.Printf("precalentamiento: no se pudo cargar %s: %v", task.name, err)
+		return
+	}
+	uc.status.Completed++
+}
+
+func (uc *WarmupUseCase) pause(d time.Duration) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	if until := time.Now().Add(d); until.After(uc.pauseUntil) {
+		uc.pauseUntil = until
+	}
+	uc.status.RateLimited++
+}
+
+func (uc *WarmupUseCase) waitPause(ctx context.Context) error {
+	uc.mutex.Lock()
+	wait := time.Until(uc.pauseUntil)
+	uc.mutex.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
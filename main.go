@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
 
-	"time"
-
+	"github.com/gerardstrujills/backend/internal/domain/services"
 	"github.com/gerardstrujills/backend/internal/infrastructure/cache"
+	"github.com/gerardstrujills/backend/internal/infrastructure/config"
 	"github.com/gerardstrujills/backend/internal/infrastructure/repositories"
+	"github.com/gerardstrujills/backend/internal/infrastructure/resilience"
+	"github.com/gerardstrujills/backend/internal/infrastructure/search"
+	"github.com/gerardstrujills/backend/internal/interfaces/http/cursor"
 	"github.com/gerardstrujills/backend/internal/interfaces/http/handlers"
 	"github.com/gerardstrujills/backend/internal/interfaces/http/routes"
 	"github.com/gerardstrujills/backend/internal/usecases"
@@ -15,27 +21,81 @@ import (
 
 func main() {
 	// Configuracion
-	const (
-		serverPort = ":8080"
-		pokeAPIURL = "https://pokeapi.co/api/v2"
-		cacheSize  = 1000
-		cacheTTL   = 15 * time.Minute
-	)
-
-	// Inicializar cache LRU
-	cacheService, err := cache.NewLRUCache(cacheSize, cacheTTL)
+	cfg := config.Load()
+
+	// Inicializar cache segun el backend configurado
+	backend, err := newCacheService(cfg)
 	if err != nil {
 		log.Fatalf("no se pudo inicializar la cache: %v", err)
 	}
+	cacheService := cache.NewStatsCache(backend)
 
 	// Inicializar repositorio
-	pokemonRepo := repositories.NewPokemonAPIRepository(pokeAPIURL)
+	breaker := resilience.NewCircuitBreaker(resilience.BreakerSettings{
+		Window:           cfg.Breaker.Window,
+		MinRequests:      cfg.Breaker.MinRequests,
+		FailureRatio:     cfg.Breaker.FailureRatio,
+		OpenTimeout:      cfg.Breaker.OpenTimeout,
+		HalfOpenRequests: cfg.Breaker.HalfOpenRequests,
+	})
+	rateLimiter := resilience.NewRateLimiter(cfg.RateLimit.Rate, cfg.RateLimit.Burst, cfg.RateLimit.MaxWait)
+	pokemonRepo := repositories.NewPokemonAPIRepository(cfg.PokeAPIURL, repositories.RetryPolicy{
+		MaxAttempts:    cfg.Retry.MaxAttempts,
+		BaseDelay:      cfg.Retry.BaseDelay,
+		MaxDelay:       cfg.Retry.MaxDelay,
+		AttemptTimeout: cfg.Retry.AttemptTimeout,
+		MaxRetryAfter:  cfg.Retry.MaxRetryAfter,
+	}, breaker.Transport(http.DefaultTransport), rateLimiter)
+
+	// Inicializar indice de busqueda
+	searchIndex := search.NewNameIndex(pokemonRepo, cfg.SearchIndexRefresh)
 
 	// Inicializar casos de uso
-	pokemonUseCase := usecases.NewPokemonUseCase(pokemonRepo, cacheService)
+	ttlPolicy := services.NewTTLPolicy(cfg.CacheTTLPolicies, cfg.CacheTTLJitter)
+	pokemonUseCase := usecases.NewPokemonUseCase(pokemonRepo, cacheService, searchIndex, ttlPolicy)
+	typeUseCase := usecases.NewTypeUseCase(pokemonRepo, pokemonUseCase)
+	teamUseCase := usecases.NewTeamUseCase(pokemonUseCase, typeUseCase)
+	comparisonUseCase := usecases.NewComparisonUseCase(pokemonUseCase, typeUseCase)
+	cacheAdminUseCase := usecases.NewCacheAdminUseCase(cacheService, cacheService)
+
+	// Precalentar la cache en segundo plano
+	warmupUseCase := usecases.NewWarmupUseCase(pokemonUseCase, typeUseCase, searchIndex, cfg.Warmup.Pokemon, cfg.Warmup.Concurrency)
+	if cfg.Warmup.Enabled {
+		warmupUseCase.Start(context.Background())
+	} else {
+		warmupUseCase.Disable()
+	}
 
 	// Inicializar handlers
-	pokemonHandler := handlers.NewPokemonHandler(pokemonUseCase)
+	if cfg.CursorSecret == "" {
+		log.Printf("CURSOR_SECRET no definido: los cursores no seran validos entre reinicios ni replicas")
+	}
+	cursorCodec := cursor.NewCodec([]byte(cfg.CursorSecret), cfg.CursorTTL)
+	pokemonHandler := handlers.NewPokemonHandler(pokemonUseCase, comparisonUseCase, cursorCodec, cfg.PublicBaseURL)
+	typeHandler := handlers.NewTypeHandler(typeUseCase)
+	teamHandler := handlers.NewTeamHandler(teamUseCase)
+	metricsProviders := map[string]services.MetricsProvider{
+		"coalescing":      pokemonUseCase,
+		"cache":           cacheService,
+		"warmup":          warmupUseCase,
+		"circuit_breaker": breaker,
+		"pokeapi":         pokemonRepo,
+	}
+	if rateLimiter != nil {
+		metricsProviders["rate_limiter"] = rateLimiter
+	}
+	if usage, ok := backend.(services.MetricsProvider); ok {
+		metricsProviders["cache_usage"] = usage
+	}
+	metricsHandler := handlers.NewMetricsHandler(metricsProviders)
+	if cfg.AdminToken == "" {
+		log.Printf("ADMIN_TOKEN no definido: las rutas /admin estan deshabilitadas")
+	}
+	adminHandler := handlers.NewAdminHandler(cacheAdminUseCase)
+	warmupHandler := handlers.NewWarmupHandler(warmupUseCase)
+	healthHandler := handlers.NewHealthHandler(map[string]services.HealthReporter{
+		"pokeapi": breaker,
+	})
 
 	// Configurar Gin
 	if gin.Mode() == gin.ReleaseMode {
@@ -45,16 +105,65 @@ func main() {
 	r := gin.New()
 
 	// Configurar rutas
-	routes.SetupRoutes(r, pokemonHandler)
+	routes.SetupRoutes(r, pokemonHandler, typeHandler, teamHandler, metricsHandler, adminHandler, cfg.AdminToken, warmupHandler, healthHandler)
 
-	log.Printf("Hortifrut Backend port %s", serverPort)
+	log.Printf("Hortifrut Backend port %s", cfg.ServerPort)
 	log.Printf("   GET /health")
+	log.Printf("   GET /ready")
+	log.Printf("   GET /warmup")
+	log.Printf("   GET /metrics")
 	log.Printf("   GET /api/v1/pokemon?limit=20&offset=0")
 	log.Printf("   GET /api/v1/pokemon/:id")
+	log.Printf("   GET /api/v1/pokemon/:id/species")
+	log.Printf("   GET /api/v1/pokemon/:id/evolutions")
+	log.Printf("   GET /api/v1/pokemon/:id/weaknesses")
 	log.Printf("   GET /api/v1/pokemon/name/:name")
+	log.Printf("   GET /api/v1/pokemon/name/:name/species")
 	log.Printf("   GET /api/v1/pokemon/search?q=pika&limit=10&offset=0")
+	log.Printf("   GET /api/v1/pokemon/compare?ids=25,26,133")
+	log.Printf("   GET /api/v1/types")
+	log.Printf("   GET /api/v1/types/:name")
+	log.Printf("  POST /api/v1/teams/analyze")
+	log.Printf("   GET /admin/cache/stats")
+	log.Printf("DELETE /admin/cache/:key")
+	log.Printf("DELETE /admin/cache?prefix=pokemon:search")
+	log.Printf("  POST /admin/cache/clear")
 
-	if err := r.Run(serverPort); err != nil {
+	if err := r.Run(cfg.ServerPort); err != nil {
 		log.Fatalf("no se pudo iniciar el servidor: %v", err)
 	}
 }
+
+// Crea la implementacion de CacheService seleccionada en la configuracion
+func newCacheService(cfg *config.Config) (services.CacheService, error) {
+	switch cfg.CacheBackend {
+	case "memory":
+		return cache.NewLRUCache(cfg.CacheSize, cfg.CacheMaxBytes, cfg.CacheTTL, cfg.CacheStaleTTL)
+	case "disk":
+		return cache.NewDiskCache(cfg.CacheDir, cfg.CacheSize, cfg.CacheTTL, cfg.CacheStaleTTL)
+	case "redis":
+		return newRedisCache(cfg)
+	case "tiered":
+		local, err := cache.NewLRUCache(cfg.CacheSize, cfg.CacheMaxBytes, cfg.CacheTTL, cfg.CacheStaleTTL)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := newRedisCache(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewTieredCache(local, shared, cfg.Redis.InvalidationChannel), nil
+	default:
+		return nil, fmt.Errorf("backend de cache desconocido: %s", cfg.CacheBackend)
+	}
+}
+
+func newRedisCache(cfg *config.Config) (*cache.RedisCache, error) {
+	return cache.NewRedisCache(cache.RedisOptions{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+		PoolSize: cfg.Redis.PoolSize,
+		Timeout:  cfg.Redis.Timeout,
+	}, cfg.Redis.KeyPrefix, cfg.CacheTTL, cfg.CacheStaleTTL)
+}